@@ -0,0 +1,136 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+var annotateOutput bool
+var attributesPath string
+
+// Fill colours used by --annotate.
+const (
+	annotateMainColor     = "#ccffcc"
+	annotateTestOnlyColor = "#e1bee7"
+)
+
+// nodeAttribute is the value for a module in an --attributes file, e.g.
+// {"github.com/foo/bar": {"color": "#ffcdd2", "label": "CVE-2024-1234"}}.
+type nodeAttribute struct {
+	Color string `json:"color,omitempty"`
+	Label string `json:"label,omitempty"`
+}
+
+// dotAnnotations holds what --annotate adds to DOT nodes.
+type dotAnnotations struct {
+	Versions    map[string]string
+	TestOnly    map[string]bool
+	MainModules map[string]bool
+	Attributes  map[string]nodeAttribute
+}
+
+func loadNodeAttributes(path string) (map[string]nodeAttribute, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attributes file: %w", err)
+	}
+	attributes := map[string]nodeAttribute{}
+	if err := json.Unmarshal(data, &attributes); err != nil {
+		return nil, fmt.Errorf("failed to parse attributes file %s: %w", path, err)
+	}
+	return attributes, nil
+}
+
+// newDOTAnnotations classifies modules as test-only and loads the
+// attributes file, if any.
+func newDOTAnnotations(overview *DependencyOverview, modules []string, attributesFile string) (*dotAnnotations, error) {
+	a := &dotAnnotations{
+		Versions:    overview.Versions,
+		MainModules: map[string]bool{},
+		Attributes:  map[string]nodeAttribute{},
+	}
+	for _, m := range overview.MainModules {
+		a.MainModules[m] = true
+	}
+	var deps []string
+	for _, m := range modules {
+		if !a.MainModules[m] {
+			deps = append(deps, m)
+		}
+	}
+	sort.Strings(deps)
+	testOnly, err := classifyTestDeps(deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to classify dependencies: %w", err)
+	}
+	a.TestOnly = testOnly
+	if attributesFile != "" {
+		if a.Attributes, err = loadNodeAttributes(attributesFile); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// nodeAttributes returns the DOT attribute list for module. fill is the
+// colour the caller already uses for the node; it is kept unless the module
+// has a user-supplied colour, and replaced by the main module or test-only
+// colour when empty.
+func (a *dotAnnotations) nodeAttributes(module, fill string) string {
+	label := module
+	if v := a.Versions[module]; v != "" {
+		label += "\\n" + v
+	}
+	attr := a.Attributes[module]
+	if attr.Label != "" {
+		label += "\\n" + attr.Label
+	}
+
+	style := "filled"
+	var extra []string
+	switch {
+	case a.MainModules[module]:
+		extra = append(extra, "peripheries=2")
+		if fill == "" {
+			fill = annotateMainColor
+		}
+	case a.TestOnly[module]:
+		style = "filled,dashed"
+		if fill == "" {
+			fill = annotateTestOnlyColor
+		}
+	}
+	if attr.Color != "" {
+		fill = attr.Color
+	}
+	if fill == "" {
+		fill = "white"
+	}
+
+	parts := []string{
+		fmt.Sprintf("label=\"%s\"", strings.ReplaceAll(label, "\"", "\\\"")),
+		fmt.Sprintf("style=\"%s\"", style),
+		// The colour may come from --attributes, so escape it like the label.
+		fmt.Sprintf("fillcolor=\"%s\"", strings.ReplaceAll(fill, "\"", "\\\"")),
+	}
+	return strings.Join(append(parts, extra...), ", ")
+}
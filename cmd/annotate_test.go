@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func testAnnotations() *dotAnnotations {
+	return &dotAnnotations{
+		Versions:    map[string]string{"lib": "v1.2.3", "testlib": "v0.1.0"},
+		TestOnly:    map[string]bool{"testlib": true},
+		MainModules: map[string]bool{"main": true},
+		Attributes: map[string]nodeAttribute{
+			"lib":    {Color: "#ffcdd2", Label: "CVE-2024-1234"},
+			"quoted": {Color: `red", shape="circle`, Label: `say "hi"`},
+		},
+	}
+}
+
+func Test_dotAnnotations_nodeAttributes(t *testing.T) {
+	a := testAnnotations()
+	tests := []struct {
+		module, fill, want string
+	}{
+		{"main", "", `label="main", style="filled", fillcolor="#ccffcc", peripheries=2`},
+		{"main", "yellow", `label="main", style="filled", fillcolor="yellow", peripheries=2`},
+		{"testlib", "", `label="testlib\nv0.1.0", style="filled,dashed", fillcolor="#e1bee7"`},
+		{"lib", "#ffffcc", `label="lib\nv1.2.3\nCVE-2024-1234", style="filled", fillcolor="#ffcdd2"`},
+		{"other", "", `label="other", style="filled", fillcolor="white"`},
+		{"quoted", "", `label="quoted\nsay \"hi\"", style="filled", fillcolor="red\", shape=\"circle"`},
+	}
+	for _, tt := range tests {
+		if got := a.nodeAttributes(tt.module, tt.fill); got != tt.want {
+			t.Errorf("nodeAttributes(%q, %q) = %s, want %s", tt.module, tt.fill, got, tt.want)
+		}
+	}
+}
+
+func Test_loadNodeAttributes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "attrs.json")
+	if err := os.WriteFile(path, []byte(`{"example.com/a": {"color": "red", "label": "flagged"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	attrs, err := loadNodeAttributes(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attrs["example.com/a"] != (nodeAttribute{Color: "red", Label: "flagged"}) {
+		t.Errorf("unexpected attributes: %+v", attrs)
+	}
+
+	if err := os.WriteFile(path, []byte(`["not", "a", "map"]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadNodeAttributes(path); err == nil {
+		t.Error("expected an error for a malformed attributes file")
+	}
+}
+
+func Test_outputWhyDOT_annotated(t *testing.T) {
+	result := WhyResult{
+		Target:      "testlib",
+		Found:       true,
+		MainModules: []string{"main"},
+		Paths:       []WhyPath{{Path: []string{"main", "lib", "testlib"}}},
+	}
+	out := captureStdout(t, func() {
+		if err := outputWhyDOT(result, testAnnotations()); err != nil {
+			t.Fatal(err)
+		}
+	})
+	for _, want := range []string{
+		`"main" [label="main", style="filled", fillcolor="#ccffcc", peripheries=2];`,
+		`"testlib" [label="testlib\nv0.1.0", style="filled,dashed", fillcolor="#ffffcc"];`,
+		`"lib" [label="lib\nv1.2.3\nCVE-2024-1234", style="filled", fillcolor="#ffcdd2"];`,
+		`"main" -> "lib";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("missing %s in:\n%s", want, out)
+		}
+	}
+}
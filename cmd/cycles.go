@@ -19,8 +19,10 @@ package cmd
 import (
 	"encoding/json"
 	"fmt"
+	"os"
 	"sort"
 	"strconv"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -29,6 +31,15 @@ var jsonOutputCycles bool
 var summaryOutputCycles bool
 var maxCycleLength int
 var cyclesTopN int
+var sccOutputCycles bool
+var sccThreshold int
+var suggestBreaksCycles bool
+var perCycleOutput bool
+var versionedCycles bool
+var cyclesBaselinePath string
+var updateCyclesBaseline bool
+var maxCyclesLimit int
+var cyclesTimeout time.Duration
 
 // cyclesFinder implements Johnson's algorithm for finding all elementary cycles
 // in a directed graph. Time complexity: O((V+E)(C+1)) where C is the number of cycles.
@@ -40,7 +51,34 @@ type cyclesFinder struct {
 	blockedMap []map[int]bool
 	stack      []int
 	cycles     []Chain
-	maxLength  int
+	search     cycleSearch
+	found      int
+	steps      int
+	stoppedBy  string
+}
+
+// Reasons for cycle enumeration to stop early.
+const (
+	cycleStopMaxCycles = "max-cycles"
+	cycleStopTimeout   = "timeout"
+)
+
+// cycleDeadlineCheckInterval is the number of search steps between two
+// checks of the deadline, so that the clock is not read on every step.
+const cycleDeadlineCheckInterval = 1024
+
+// cycleSearch bounds and directs the output of findCycles.
+type cycleSearch struct {
+	// MaxLength limits cycles to this many modules (0 = no limit).
+	MaxLength int
+	// MaxCycles stops the search once this many cycles are found (0 = no
+	// limit).
+	MaxCycles int
+	// Deadline stops the search once passed (zero = no deadline).
+	Deadline time.Time
+	// OnCycle is called with every cycle as soon as it is found. When set,
+	// cycles are not accumulated.
+	OnCycle func(Chain)
 }
 
 type cycleSummary struct {
@@ -48,6 +86,15 @@ type cycleSummary struct {
 	ByLength        map[string]int     `json:"byLength"`
 	TwoNodeCycles   [][]string         `json:"twoNodeCycles"`
 	TopParticipants []cycleParticipant `json:"topParticipants"`
+	Truncated       bool               `json:"truncated,omitempty"`
+	TruncatedBy     string             `json:"truncatedBy,omitempty"`
+}
+
+// cycleStreamEnd is the last line of the JSON Lines output of cycles --json.
+type cycleStreamEnd struct {
+	TotalCycles int    `json:"totalCycles"`
+	Truncated   bool   `json:"truncated,omitempty"`
+	TruncatedBy string `json:"truncatedBy,omitempty"`
 }
 
 type cycleParticipant struct {
@@ -59,60 +106,297 @@ type cycleParticipant struct {
 var cyclesCmd = &cobra.Command{
 	Use:   "cycles",
 	Short: "Prints cycles in dependency chains.",
-	Long:  `Will show all the cycles in the dependencies of the project.`,
+	Long: `Will show all the cycles in the dependencies of the project.
+
+	Large module graphs can contain millions of elementary cycles. Use --scc to
+	report only the strongly connected components, with the shortest cycle
+	through each member. --summary switches to this report automatically once
+	more than --scc-threshold cycles are found; with --max-length it then only
+	lists the shortest cycles within the limit.
+
+	--suggest-breaks lists requirements whose removal makes the graph acyclic:
+	a minimum set for components of up to 16 modules and a heuristic one
+	beyond that, ranked by the number of cycles each edge is part of.
+
+	--dot, --svg and --mermaid draw each strongly connected component (or each
+	cycle with --per-cycle) as its own group, with edges labelled by the number
+	of cycles they are part of and two-node cycles highlighted.
+
+	--versioned looks for cycles across the requirements of every version of
+	the modules in the build listed in "go mod graph", not only the selected
+	ones. Each cycle is labelled live if the selected versions still form it,
+	or historical if it only exists through requirements of superseded
+	versions.
+
+	--baseline compares the cycles with the ones recorded in a file, reporting
+	new and resolved cycles, and fails if there are new ones. Cycles are
+	compared regardless of the module they start at. Use --update-baseline to
+	write the current cycles to the file instead. The file records
+	--max-length, and only runs with the same limit are compared. With
+	--max-cycles or --timeout, a run that stops early fails instead of
+	comparing an incomplete set of cycles.
+
+	Cycles are printed as they are found; --json writes one JSON object per
+	line followed by a line with the total. --max-cycles and --timeout stop the
+	enumeration early, in which case the output is marked as truncated.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 
 		if len(args) != 0 {
 			return fmt.Errorf("cycles does not take any arguments")
 		}
 
-		overview := getDepInfo(mainModules)
+		goModGraph := getGoModGraph()
+		overview := getDepInfoFromGraph(goModGraph, mainModules)
 		if maxCycleLength != 0 && maxCycleLength < 2 {
 			return fmt.Errorf("--max-length must be >= 2 (minimum cycle length is 2)")
 		}
 		if summaryOutputCycles && cyclesTopN <= 0 {
 			return fmt.Errorf("-n must be > 0")
 		}
+		if sccThreshold < 0 {
+			return fmt.Errorf("--scc-threshold must be >= 0")
+		}
+		if maxCyclesLimit < 0 {
+			return fmt.Errorf("--max-cycles must be >= 0")
+		}
+		if cyclesTimeout < 0 {
+			return fmt.Errorf("--timeout must be >= 0")
+		}
+		limited := maxCyclesLimit != 0 || cyclesTimeout != 0
+		if limited && (sccOutputCycles || suggestBreaksCycles) {
+			return fmt.Errorf("--max-cycles and --timeout cannot be used with --scc or --suggest-breaks")
+		}
+		if sccOutputCycles && (summaryOutputCycles || maxCycleLength != 0) {
+			return fmt.Errorf("--scc cannot be used with --summary or --max-length")
+		}
+		if suggestBreaksCycles && (sccOutputCycles || summaryOutputCycles || maxCycleLength != 0) {
+			return fmt.Errorf("--suggest-breaks cannot be used with --scc, --summary or --max-length")
+		}
+		visualOutputs := 0
+		for _, enabled := range []bool{dotOutput, svgOutput, mermaidOutput} {
+			if enabled {
+				visualOutputs++
+			}
+		}
+		if visualOutputs > 1 {
+			return fmt.Errorf("--dot, --svg and --mermaid are mutually exclusive")
+		}
+		if visualOutputs == 1 && (jsonOutputCycles || summaryOutputCycles || sccOutputCycles || suggestBreaksCycles) {
+			return fmt.Errorf("--dot, --svg and --mermaid cannot be used with --json, --summary, --scc or --suggest-breaks")
+		}
+		if perCycleOutput && visualOutputs == 0 {
+			return fmt.Errorf("--per-cycle requires --dot, --svg or --mermaid")
+		}
+		if versionedCycles && (visualOutputs == 1 || summaryOutputCycles || sccOutputCycles || suggestBreaksCycles) {
+			return fmt.Errorf("--versioned cannot be used with --summary, --scc, --suggest-breaks, --dot, --svg or --mermaid")
+		}
+		if updateCyclesBaseline && cyclesBaselinePath == "" {
+			return fmt.Errorf("--update-baseline requires --baseline")
+		}
+		if cyclesBaselinePath != "" && (visualOutputs == 1 || summaryOutputCycles || sccOutputCycles || suggestBreaksCycles || versionedCycles) {
+			return fmt.Errorf("--baseline cannot be used with --summary, --scc, --suggest-breaks, --versioned, --dot, --svg or --mermaid")
+		}
 		if len(overview.MainModules) == 0 {
 			return fmt.Errorf("no main modules remain after exclusions; adjust --exclude-modules or --mainModules")
 		}
 
-		cycles := findAllCyclesWithMaxLength(overview.Graph, maxCycleLength)
-		var summary cycleSummary
-		if summaryOutputCycles {
-			summary = summarizeCycles(cycles, cyclesTopN)
+		search := cycleSearch{MaxLength: maxCycleLength, MaxCycles: maxCyclesLimit}
+		if cyclesTimeout > 0 {
+			search.Deadline = time.Now().Add(cyclesTimeout)
 		}
 
-		if !jsonOutputCycles && !summaryOutputCycles {
-			fmt.Println("All cycles in dependencies are: ")
-			for _, c := range cycles {
-				printChain(c)
+		if versionedCycles {
+			cycles, stoppedBy := findVersionedCycles(parseVersionedGraph(goModGraph), overview.Versions, excludeModules, search)
+			if !jsonOutputCycles {
+				printVersionedCycles(cycles, stoppedBy)
+				return nil
 			}
+			outputRaw, err := json.MarshalIndent(versionedCycleReport{
+				Cycles:      cycles,
+				Truncated:   stoppedBy != "",
+				TruncatedBy: stoppedBy,
+			}, "", "\t")
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(outputRaw))
+			return nil
 		}
-
-		if !jsonOutputCycles && summaryOutputCycles {
-			printCycleSummary(summary)
+		if cyclesBaselinePath != "" {
+			if updateCyclesBaseline {
+				count, err := updateCycleBaseline(overview.Graph, search, cyclesBaselinePath)
+				if err != nil {
+					return err
+				}
+				fmt.Printf("Updated %s with %d cycles\n", cyclesBaselinePath, count)
+				return nil
+			}
+			diff, err := checkCycleBaseline(overview.Graph, search, cyclesBaselinePath)
+			if err != nil {
+				return err
+			}
+			if jsonOutputCycles {
+				outputRaw, err := json.MarshalIndent(diff, "", "\t")
+				if err != nil {
+					return err
+				}
+				fmt.Print(string(outputRaw))
+			} else {
+				printCycleBaselineDiff(diff)
+			}
+			if len(diff.New) > 0 {
+				// The report above explains the failure; skip the usage text.
+				cmd.SilenceUsage = true
+				return fmt.Errorf("%d new dependency cycles not in %s", len(diff.New), cyclesBaselinePath)
+			}
+			return nil
 		}
 
-		if jsonOutputCycles {
-			outputObj := map[string]interface{}{}
-			if !summaryOutputCycles {
-				outputObj["cycles"] = cycles
+		if visualOutputs == 1 {
+			// Like --summary, bound the enumeration by --scc-threshold; past
+			// it the components are drawn whole, with partial counts.
+			if search.MaxCycles == 0 && sccThreshold > 0 {
+				search.MaxCycles = sccThreshold
 			}
-			if summaryOutputCycles {
-				outputObj["summary"] = summary
+			cycles, stoppedBy := findCycles(overview.Graph, search)
+			if stoppedBy != "" {
+				fmt.Fprintf(os.Stderr, "Warning: cycle enumeration stopped by %s after %d cycles; the cycle counts in the diagram are lower bounds\n", stoppedBy, len(cycles))
 			}
-
-			outputRaw, err := json.MarshalIndent(outputObj, "", "\t")
+			groups := buildCycleGroups(overview.Graph, cycles, perCycleOutput, stoppedBy != "")
+			switch {
+			case dotOutput:
+				fmt.Print(getDOTForCycles(groups))
+			case svgOutput:
+				fmt.Print(getSVGForCycles(groups, overview.MainModules))
+			case mermaidOutput:
+				fmt.Print(getMermaidForCycles(groups))
+			}
+			return nil
+		}
+		if suggestBreaksCycles {
+			breaks := suggestCycleBreaks(overview.Graph, sccThreshold)
+			if !jsonOutputCycles {
+				printCycleBreaks(breaks)
+				return nil
+			}
+			outputRaw, err := json.MarshalIndent(map[string]interface{}{"breaks": breaks}, "", "\t")
 			if err != nil {
 				return err
 			}
 			fmt.Print(string(outputRaw))
+			return nil
 		}
-		return nil
+		if sccOutputCycles {
+			return outputCycleComponents(findCycleComponents(overview.Graph), false, 0)
+		}
+
+		if summaryOutputCycles {
+			return outputCycleSummary(overview.Graph, search)
+		}
+		return streamCycles(overview.Graph, search)
 	},
 }
 
+// outputCycleSummary enumerates cycles into an incremental summary. Once
+// more than --scc-threshold cycles are found, it reports the strongly
+// connected components instead.
+func outputCycleSummary(graph map[string][]string, search cycleSearch) error {
+	builder := newCycleSummaryBuilder()
+	search.OnCycle = builder.add
+	fallback := sccThreshold > 0 && (search.MaxCycles == 0 || search.MaxCycles > sccThreshold)
+	if fallback {
+		search.MaxCycles = sccThreshold + 1
+	}
+	_, stoppedBy := findCycles(graph, search)
+	if fallback && stoppedBy == cycleStopMaxCycles {
+		components := findCycleComponents(graph)
+		if search.MaxLength > 0 {
+			components = limitCycleComponents(components, search.MaxLength)
+		}
+		return outputCycleComponents(components, true, search.MaxLength)
+	}
+
+	summary := builder.summary(cyclesTopN)
+	summary.Truncated, summary.TruncatedBy = stoppedBy != "", stoppedBy
+	if !jsonOutputCycles {
+		printCycleSummary(summary)
+		return nil
+	}
+	outputRaw, err := json.MarshalIndent(map[string]interface{}{"summary": summary}, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(outputRaw))
+	return nil
+}
+
+// streamCycles prints cycles as they are found, as text or JSON Lines.
+func streamCycles(graph map[string][]string, search cycleSearch) error {
+	count := 0
+	var encodeErr error
+	encoder := json.NewEncoder(os.Stdout)
+	if jsonOutputCycles {
+		search.OnCycle = func(c Chain) {
+			count++
+			if err := encoder.Encode(struct {
+				Cycle Chain `json:"cycle"`
+			}{c}); err != nil && encodeErr == nil {
+				encodeErr = err
+			}
+		}
+	} else {
+		fmt.Println("All cycles in dependencies are: ")
+		search.OnCycle = func(c Chain) {
+			count++
+			printChain(c)
+		}
+	}
+	_, stoppedBy := findCycles(graph, search)
+	if encodeErr != nil {
+		return encodeErr
+	}
+
+	if jsonOutputCycles {
+		return encoder.Encode(cycleStreamEnd{TotalCycles: count, Truncated: stoppedBy != "", TruncatedBy: stoppedBy})
+	}
+	if stoppedBy != "" {
+		fmt.Printf("\n(enumeration truncated by %s after %d cycles)\n", stoppedBy, count)
+	}
+	return nil
+}
+
+// outputCycleComponents prints the --scc report. thresholdExceeded is set
+// when --summary fell back to it because there were too many cycles, and
+// maxLength is the --max-length the components were limited to (0 = none).
+func outputCycleComponents(components []cycleComponent, thresholdExceeded bool, maxLength int) error {
+	if jsonOutputCycles {
+		outputObj := map[string]interface{}{
+			"components": components,
+		}
+		if thresholdExceeded {
+			outputObj["thresholdExceeded"] = true
+			outputObj["sccThreshold"] = sccThreshold
+		}
+		if maxLength > 0 {
+			outputObj["maxLength"] = maxLength
+		}
+		outputRaw, err := json.MarshalIndent(outputObj, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(outputRaw))
+		return nil
+	}
+	switch {
+	case thresholdExceeded && maxLength > 0:
+		fmt.Printf("More than %d cycles of at most %d modules found; showing strongly connected components instead, with the shortest cycles within --max-length (use --scc-threshold 0 to count all cycles).\n\n", sccThreshold, maxLength)
+	case thresholdExceeded:
+		fmt.Printf("More than %d cycles found; showing strongly connected components instead (use --scc-threshold 0 to count all cycles).\n\n", sccThreshold)
+	}
+	printCycleComponents(components)
+	return nil
+}
+
 // findAllCycles finds all elementary cycles in the graph using Johnson's algorithm.
 // Time complexity: O((V+E)(C+1)) where C is the number of cycles.
 func findAllCycles(graph map[string][]string) []Chain {
@@ -120,6 +404,25 @@ func findAllCycles(graph map[string][]string) []Chain {
 }
 
 func findAllCyclesWithMaxLength(graph map[string][]string, maxLength int) []Chain {
+	cycles, _ := findCycles(graph, cycleSearch{MaxLength: maxLength})
+	return cycles
+}
+
+// findCyclesWithLimit is findAllCyclesWithMaxLength, but gives up once more
+// than limit cycles have been found (0 = no limit) and reports whether it did.
+func findCyclesWithLimit(graph map[string][]string, maxLength, limit int) ([]Chain, bool) {
+	search := cycleSearch{MaxLength: maxLength}
+	if limit > 0 {
+		search.MaxCycles = limit + 1
+	}
+	cycles, stoppedBy := findCycles(graph, search)
+	return cycles, stoppedBy != ""
+}
+
+// findCycles enumerates the elementary cycles of graph within the bounds of
+// search. It returns the cycles found, unless search.OnCycle consumed them,
+// and why the enumeration stopped early ("" if it completed).
+func findCycles(graph map[string][]string, search cycleSearch) ([]Chain, string) {
 	// Collect all nodes
 	nodeSet := make(map[string]bool)
 	for node := range graph {
@@ -152,7 +455,7 @@ func findAllCyclesWithMaxLength(graph map[string][]string, maxLength int) []Chai
 		blockedMap: make([]map[int]bool, len(nodes)),
 		stack:      make([]int, 0),
 		cycles:     make([]Chain, 0),
-		maxLength:  maxLength,
+		search:     search,
 	}
 
 	for i := range cf.blockedMap {
@@ -160,7 +463,7 @@ func findAllCyclesWithMaxLength(graph map[string][]string, maxLength int) []Chai
 	}
 
 	// Johnson's algorithm: iterate through each node as potential cycle start
-	for startIdx := 0; startIdx < len(nodes); startIdx++ {
+	for startIdx := 0; startIdx < len(nodes) && cf.stoppedBy == ""; startIdx++ {
 		// Find SCCs in subgraph induced by nodes[startIdx:]
 		subgraphSCC := cf.findSCCContaining(startIdx)
 
@@ -180,7 +483,7 @@ func findAllCyclesWithMaxLength(graph map[string][]string, maxLength int) []Chai
 		}
 	}
 
-	return cf.cycles
+	return cf.cycles, cf.stoppedBy
 }
 
 // findSCCContaining finds the SCC containing startIdx in the subgraph induced by nodes >= startIdx
@@ -281,7 +584,16 @@ func (cf *cyclesFinder) circuit(v, start int, sccSet map[int]bool) bool {
 	cf.stack = append(cf.stack, v)
 	cf.blocked[v] = true
 
+	cf.steps++
+	if !cf.search.Deadline.IsZero() && cf.steps%cycleDeadlineCheckInterval == 0 && time.Now().After(cf.search.Deadline) {
+		cf.stoppedBy = cycleStopTimeout
+	}
+
+	maxLength := cf.search.MaxLength
 	for _, neighbor := range cf.graph[cf.indexNode[v]] {
+		if cf.stoppedBy != "" {
+			break
+		}
 		neighborIdx := cf.nodeIndex[neighbor]
 
 		// Only consider nodes in the current SCC
@@ -291,16 +603,24 @@ func (cf *cyclesFinder) circuit(v, start int, sccSet map[int]bool) bool {
 
 		if neighborIdx == start {
 			// Found a cycle
-			if cf.maxLength == 0 || len(cf.stack) <= cf.maxLength {
+			if maxLength == 0 || len(cf.stack) <= maxLength {
 				cycle := make(Chain, len(cf.stack)+1)
 				for i, idx := range cf.stack {
 					cycle[i] = cf.indexNode[idx]
 				}
 				cycle[len(cf.stack)] = cf.indexNode[start]
-				cf.cycles = append(cf.cycles, cycle)
+				if cf.search.OnCycle != nil {
+					cf.search.OnCycle(cycle)
+				} else {
+					cf.cycles = append(cf.cycles, cycle)
+				}
 				found = true
+				cf.found++
+				if cf.search.MaxCycles > 0 && cf.found >= cf.search.MaxCycles {
+					cf.stoppedBy = cycleStopMaxCycles
+				}
 			}
-		} else if !cf.blocked[neighborIdx] && (cf.maxLength == 0 || len(cf.stack) < cf.maxLength) {
+		} else if !cf.blocked[neighborIdx] && (maxLength == 0 || len(cf.stack) < maxLength) {
 			if cf.circuit(neighborIdx, start, sccSet) {
 				found = true
 			}
@@ -344,39 +664,64 @@ func containsInt(slice []int, val int) bool {
 }
 
 func summarizeCycles(cycles []Chain, topN int) cycleSummary {
-	byLength := map[string]int{}
-	twoNodeSeen := map[string]bool{}
-	var twoNodeCycles [][]string
-	participantCounts := map[string]int{}
-
+	builder := newCycleSummaryBuilder()
 	for _, cycle := range cycles {
-		if len(cycle) < 2 {
-			continue
-		}
-		cycleLen := len(cycle) - 1
-		byLength[strconv.Itoa(cycleLen)]++
+		builder.add(cycle)
+	}
+	return builder.summary(topN)
+}
 
-		seenInCycle := map[string]bool{}
-		for _, module := range cycle[:len(cycle)-1] {
-			if !seenInCycle[module] {
-				participantCounts[module]++
-				seenInCycle[module] = true
-			}
+// cycleSummaryBuilder accumulates a cycleSummary one cycle at a time, so
+// that cycles do not have to be kept in memory.
+type cycleSummaryBuilder struct {
+	total             int
+	byLength          map[string]int
+	twoNodeSeen       map[string]bool
+	twoNodeCycles     [][]string
+	participantCounts map[string]int
+}
+
+func newCycleSummaryBuilder() *cycleSummaryBuilder {
+	return &cycleSummaryBuilder{
+		byLength:          map[string]int{},
+		twoNodeSeen:       map[string]bool{},
+		participantCounts: map[string]int{},
+	}
+}
+
+func (b *cycleSummaryBuilder) add(cycle Chain) {
+	b.total++
+	if len(cycle) < 2 {
+		return
+	}
+	cycleLen := len(cycle) - 1
+	b.byLength[strconv.Itoa(cycleLen)]++
+
+	seenInCycle := map[string]bool{}
+	for _, module := range cycle[:len(cycle)-1] {
+		if !seenInCycle[module] {
+			b.participantCounts[module]++
+			seenInCycle[module] = true
 		}
+	}
 
-		if cycleLen == 2 {
-			a, b := cycle[0], cycle[1]
-			if b < a {
-				a, b = b, a
-			}
-			key := a + "|" + b
-			if !twoNodeSeen[key] {
-				twoNodeSeen[key] = true
-				twoNodeCycles = append(twoNodeCycles, []string{a, b})
-			}
+	if cycleLen == 2 {
+		x, y := cycle[0], cycle[1]
+		if y < x {
+			x, y = y, x
+		}
+		key := x + "|" + y
+		if !b.twoNodeSeen[key] {
+			b.twoNodeSeen[key] = true
+			b.twoNodeCycles = append(b.twoNodeCycles, []string{x, y})
 		}
 	}
+}
 
+// summary returns the summary of the cycles added so far, with the topN
+// modules taking part in the most cycles.
+func (b *cycleSummaryBuilder) summary(topN int) cycleSummary {
+	twoNodeCycles := append([][]string(nil), b.twoNodeCycles...)
 	sort.Slice(twoNodeCycles, func(i, j int) bool {
 		if twoNodeCycles[i][0] == twoNodeCycles[j][0] {
 			return twoNodeCycles[i][1] < twoNodeCycles[j][1]
@@ -384,8 +729,8 @@ func summarizeCycles(cycles []Chain, topN int) cycleSummary {
 		return twoNodeCycles[i][0] < twoNodeCycles[j][0]
 	})
 
-	topParticipants := make([]cycleParticipant, 0, len(participantCounts))
-	for module, cycleCount := range participantCounts {
+	topParticipants := make([]cycleParticipant, 0, len(b.participantCounts))
+	for module, cycleCount := range b.participantCounts {
 		topParticipants = append(topParticipants, cycleParticipant{
 			Module:     module,
 			CycleCount: cycleCount,
@@ -401,8 +746,12 @@ func summarizeCycles(cycles []Chain, topN int) cycleSummary {
 		topParticipants = topParticipants[:topN]
 	}
 
+	byLength := make(map[string]int, len(b.byLength))
+	for l, n := range b.byLength {
+		byLength[l] = n
+	}
 	return cycleSummary{
-		TotalCycles:     len(cycles),
+		TotalCycles:     b.total,
 		ByLength:        byLength,
 		TwoNodeCycles:   twoNodeCycles,
 		TopParticipants: topParticipants,
@@ -410,7 +759,11 @@ func summarizeCycles(cycles []Chain, topN int) cycleSummary {
 }
 
 func printCycleSummary(summary cycleSummary) {
-	fmt.Printf("Total cycles: %d\n", summary.TotalCycles)
+	if summary.Truncated {
+		fmt.Printf("Total cycles: %d (truncated by %s)\n", summary.TotalCycles, summary.TruncatedBy)
+	} else {
+		fmt.Printf("Total cycles: %d\n", summary.TotalCycles)
+	}
 	fmt.Println("By cycle length:")
 	lengths := make([]int, 0, len(summary.ByLength))
 	for s := range summary.ByLength {
@@ -443,6 +796,18 @@ func init() {
 	cyclesCmd.Flags().BoolVarP(&jsonOutputCycles, "json", "j", false, "Get the output in JSON format")
 	cyclesCmd.Flags().BoolVar(&summaryOutputCycles, "summary", false, "Show cycle summary instead of raw cycle list")
 	cyclesCmd.Flags().IntVar(&maxCycleLength, "max-length", 0, "Limit cycles to length <= N (0 = no limit)")
+	cyclesCmd.Flags().BoolVar(&sccOutputCycles, "scc", false, "Report strongly connected components with the shortest cycle through each member instead of every cycle")
+	cyclesCmd.Flags().IntVar(&sccThreshold, "scc-threshold", defaultSCCThreshold, "With --summary, report strongly connected components instead once more than N cycles are found; with --dot, --svg or --mermaid, stop after N cycles unless --max-cycles is set; with --suggest-breaks, stop counting cycles per component after N (0 = no limit)")
+	cyclesCmd.Flags().BoolVar(&suggestBreaksCycles, "suggest-breaks", false, "Suggest a small set of requirements to remove so that the graph becomes acyclic")
+	cyclesCmd.Flags().BoolVar(&dotOutput, "dot", false, "Output the cycles in DOT format for Graphviz, one cluster per strongly connected component")
+	cyclesCmd.Flags().BoolVarP(&svgOutput, "svg", "s", false, "Output the cycles as a self-contained SVG diagram")
+	cyclesCmd.Flags().BoolVar(&mermaidOutput, "mermaid", false, "Output the cycles as a Mermaid flowchart")
+	cyclesCmd.Flags().BoolVar(&perCycleOutput, "per-cycle", false, "With --dot, --svg or --mermaid, draw each cycle as its own group instead of each component")
+	cyclesCmd.Flags().BoolVar(&versionedCycles, "versioned", false, "Find cycles across all module versions and label them live or historical")
+	cyclesCmd.Flags().StringVar(&cyclesBaselinePath, "baseline", "", "Compare cycles with the baseline file and fail on new ones")
+	cyclesCmd.Flags().BoolVar(&updateCyclesBaseline, "update-baseline", false, "Write the current cycles to the --baseline file")
+	cyclesCmd.Flags().IntVar(&maxCyclesLimit, "max-cycles", 0, "Stop after finding N cycles (0 = no limit)")
+	cyclesCmd.Flags().DurationVar(&cyclesTimeout, "timeout", 0, "Stop looking for cycles after this long, e.g. 30s (0 = no limit)")
 	cyclesCmd.Flags().IntVarP(&cyclesTopN, "top", "n", 10, "Number of top participants to show in summary")
 	cyclesCmd.Flags().StringSliceVar(&excludeModules, "exclude-modules", []string{}, "Exclude module path patterns (repeatable, supports * wildcard)")
 	cyclesCmd.Flags().StringSliceVarP(&mainModules, "mainModules", "m", []string{}, "Enter modules whose dependencies should be considered direct dependencies; defaults to the first module encountered in `go mod graph` output")
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// cycleBaseline is the file written by --update-baseline and read by
+// --baseline. Cycles are normalised and sorted so the file only changes
+// when the set of cycles does. MaxLength is the --max-length the cycles were
+// found with (0 = no limit); only runs with the same limit are compared.
+type cycleBaseline struct {
+	MaxLength int     `json:"maxLength,omitempty"`
+	Cycles    []Chain `json:"cycles"`
+}
+
+// cycleBaselineDiff is the result of comparing the current cycles with a
+// baseline.
+type cycleBaselineDiff struct {
+	Baseline string  `json:"baseline"`
+	New      []Chain `json:"new"`
+	Resolved []Chain `json:"resolved"`
+}
+
+// normalizeCycle rotates a closed cycle (first == last) so that it starts at
+// its smallest module, making the same cycle found from another starting
+// point compare equal.
+func normalizeCycle(cycle Chain) Chain {
+	if len(cycle) < 2 {
+		return cycle
+	}
+	open := cycle[:len(cycle)-1]
+	start := 0
+	for i, m := range open {
+		if m < open[start] {
+			start = i
+		}
+	}
+	normalized := make(Chain, 0, len(cycle))
+	normalized = append(normalized, open[start:]...)
+	normalized = append(normalized, open[:start]...)
+	return append(normalized, open[start])
+}
+
+// normalizeCycles normalises every cycle, drops duplicates and sorts them.
+func normalizeCycles(cycles []Chain) []Chain {
+	byKey := map[string]Chain{}
+	for _, c := range cycles {
+		n := normalizeCycle(c)
+		byKey[strings.Join(n, " -> ")] = n
+	}
+	keys := make([]string, 0, len(byKey))
+	for k := range byKey {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	normalized := make([]Chain, 0, len(keys))
+	for _, k := range keys {
+		normalized = append(normalized, byKey[k])
+	}
+	return normalized
+}
+
+func loadCycleBaseline(path string) (cycleBaseline, error) {
+	var baseline cycleBaseline
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return baseline, fmt.Errorf("failed to read baseline file: %w", err)
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return baseline, fmt.Errorf("failed to parse baseline file %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+func writeCycleBaseline(path string, cycles []Chain, maxLength int) error {
+	out, err := json.MarshalIndent(cycleBaseline{MaxLength: maxLength, Cycles: normalizeCycles(cycles)}, "", "\t")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(out, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write baseline file: %w", err)
+	}
+	return nil
+}
+
+// compareCycleBaseline returns the cycles only present in current (new) and
+// only present in baseline (resolved), normalised and sorted.
+func compareCycleBaseline(baseline, current []Chain) (newCycles, resolved []Chain) {
+	baselineSet := map[string]bool{}
+	for _, c := range normalizeCycles(baseline) {
+		baselineSet[strings.Join(c, " -> ")] = true
+	}
+	currentSet := map[string]bool{}
+	newCycles, resolved = []Chain{}, []Chain{}
+	for _, c := range normalizeCycles(current) {
+		key := strings.Join(c, " -> ")
+		currentSet[key] = true
+		if !baselineSet[key] {
+			newCycles = append(newCycles, c)
+		}
+	}
+	for _, c := range normalizeCycles(baseline) {
+		if !currentSet[strings.Join(c, " -> ")] {
+			resolved = append(resolved, c)
+		}
+	}
+	return newCycles, resolved
+}
+
+// findBaselineCycles finds the cycles to record in or compare with a
+// baseline. Both need every cycle within --max-length, so a truncated
+// enumeration is an error.
+func findBaselineCycles(graph map[string][]string, search cycleSearch) ([]Chain, error) {
+	cycles, stoppedBy := findCycles(graph, search)
+	if stoppedBy != "" {
+		return nil, fmt.Errorf("cycle enumeration stopped by %s after %d cycles; --baseline needs every cycle, so lower --max-length or raise the limit", stoppedBy, len(cycles))
+	}
+	return cycles, nil
+}
+
+// updateCycleBaseline writes the cycles found by search to path and returns
+// how many were written.
+func updateCycleBaseline(graph map[string][]string, search cycleSearch, path string) (int, error) {
+	cycles, err := findBaselineCycles(graph, search)
+	if err != nil {
+		return 0, err
+	}
+	return len(normalizeCycles(cycles)), writeCycleBaseline(path, cycles, search.MaxLength)
+}
+
+// checkCycleBaseline compares the cycles found by search with the baseline
+// in path, which must have been written with the same --max-length.
+func checkCycleBaseline(graph map[string][]string, search cycleSearch, path string) (cycleBaselineDiff, error) {
+	diff := cycleBaselineDiff{Baseline: path}
+	baseline, err := loadCycleBaseline(path)
+	if err != nil {
+		return diff, err
+	}
+	if baseline.MaxLength != search.MaxLength {
+		return diff, fmt.Errorf("%s was written with --max-length %d, but this run uses --max-length %d; compare runs with the same limit or use --update-baseline", path, baseline.MaxLength, search.MaxLength)
+	}
+	cycles, err := findBaselineCycles(graph, search)
+	if err != nil {
+		return diff, err
+	}
+	diff.New, diff.Resolved = compareCycleBaseline(baseline.Cycles, cycles)
+	return diff, nil
+}
+
+func printCycleBaselineDiff(diff cycleBaselineDiff) {
+	fmt.Printf("New cycles (not in %s): %d\n", diff.Baseline, len(diff.New))
+	for _, c := range diff.New {
+		fmt.Printf("- %s\n", strings.Join(c, " -> "))
+	}
+	fmt.Printf("Resolved cycles (in %s, no longer present): %d\n", diff.Baseline, len(diff.Resolved))
+	for _, c := range diff.Resolved {
+		fmt.Printf("- %s\n", strings.Join(c, " -> "))
+	}
+}
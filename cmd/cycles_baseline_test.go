@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeCycle(t *testing.T) {
+	for _, c := range []Chain{
+		{"B", "C", "A", "B"},
+		{"C", "A", "B", "C"},
+		{"A", "B", "C", "A"},
+	} {
+		if got := normalizeCycle(c); !isSliceSame(got, Chain{"A", "B", "C", "A"}) {
+			t.Fatalf("normalizeCycle(%v) = %v", c, got)
+		}
+	}
+	// Rotation must not change the direction of the cycle.
+	if got := normalizeCycle(Chain{"B", "A", "C", "B"}); !isSliceSame(got, Chain{"A", "C", "B", "A"}) {
+		t.Fatalf("unexpected normalisation: %v", got)
+	}
+}
+
+func TestCycleBaselineRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cycles-baseline.json")
+	baseline := []Chain{
+		{"B", "A", "B"},
+		{"C", "D", "C"},
+	}
+	if err := writeCycleBaseline(path, baseline, 0); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "{\n\t\"cycles\": [\n\t\t[\n\t\t\t\"A\",\n\t\t\t\"B\",\n\t\t\t\"A\"\n\t\t],\n\t\t[\n\t\t\t\"C\",\n\t\t\t\"D\",\n\t\t\t\"C\"\n\t\t]\n\t]\n}\n"
+	if string(data) != want {
+		t.Fatalf("unexpected baseline file:\n%s", data)
+	}
+
+	loaded, err := loadCycleBaseline(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	current := []Chain{
+		{"A", "B", "A"},
+		{"E", "F", "E"},
+	}
+	newCycles, resolved := compareCycleBaseline(loaded.Cycles, current)
+	if len(newCycles) != 1 || !isSliceSame(newCycles[0], Chain{"E", "F", "E"}) {
+		t.Fatalf("unexpected new cycles: %v", newCycles)
+	}
+	if len(resolved) != 1 || !isSliceSame(resolved[0], Chain{"C", "D", "C"}) {
+		t.Fatalf("unexpected resolved cycles: %v", resolved)
+	}
+
+	newCycles, resolved = compareCycleBaseline(loaded.Cycles, []Chain{{"B", "A", "B"}, {"D", "C", "D"}})
+	if len(newCycles) != 0 || len(resolved) != 0 {
+		t.Fatalf("rotated cycles must match the baseline, got new=%v resolved=%v", newCycles, resolved)
+	}
+}
+
+func TestLoadCycleBaselineErrors(t *testing.T) {
+	if _, err := loadCycleBaseline(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Fatal("expected an error for a missing baseline")
+	}
+	path := filepath.Join(t.TempDir(), "bad.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadCycleBaseline(path); err == nil {
+		t.Fatal("expected an error for an invalid baseline")
+	}
+}
+
+func TestCheckCycleBaselineMaxLength(t *testing.T) {
+	// A <-> B has 2 modules, C -> D -> E -> C has 3.
+	graph := map[string][]string{
+		"A": {"B"},
+		"B": {"A"},
+		"C": {"D"},
+		"D": {"E"},
+		"E": {"C"},
+	}
+	path := filepath.Join(t.TempDir(), "cycles-baseline.json")
+	count, err := updateCycleBaseline(graph, cycleSearch{MaxLength: 2}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 1 {
+		t.Fatalf("expected 1 cycle of at most 2 modules, got %d", count)
+	}
+
+	diff, err := checkCycleBaseline(graph, cycleSearch{MaxLength: 2}, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diff.New) != 0 || len(diff.Resolved) != 0 {
+		t.Fatalf("expected no changes with the same --max-length, got %+v", diff)
+	}
+	// Without the limit the longer cycle would be reported as new.
+	if _, err := checkCycleBaseline(graph, cycleSearch{}, path); err == nil || !strings.Contains(err.Error(), "--max-length 2") {
+		t.Fatalf("expected a --max-length mismatch error, got %v", err)
+	}
+}
+
+func TestCheckCycleBaselineTruncated(t *testing.T) {
+	graph := map[string][]string{
+		"A": {"B", "C"},
+		"B": {"A"},
+		"C": {"A"},
+	}
+	path := filepath.Join(t.TempDir(), "cycles-baseline.json")
+	if _, err := updateCycleBaseline(graph, cycleSearch{MaxCycles: 1}, path); err == nil {
+		t.Fatal("expected a truncated run to refuse to write the baseline")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("no baseline should have been written, stat error: %v", err)
+	}
+	if _, err := updateCycleBaseline(graph, cycleSearch{}, path); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := checkCycleBaseline(graph, cycleSearch{MaxCycles: 1}, path); err == nil || !strings.Contains(err.Error(), "stopped by max-cycles") {
+		t.Fatalf("expected a truncated comparison to fail, got %v", err)
+	}
+}
@@ -0,0 +1,296 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"math/bits"
+	"sort"
+)
+
+// feedbackArcExactMaxNodes is the largest component for which
+// --suggest-breaks searches for a minimum feedback arc set. The search is
+// exponential in the number of modules; larger components use a heuristic.
+const feedbackArcExactMaxNodes = 16
+
+// cycleBreak is a requirement that --suggest-breaks proposes to remove.
+type cycleBreak struct {
+	From       string `json:"from"`
+	To         string `json:"to"`
+	CycleCount int    `json:"cycleCount"`
+}
+
+// componentBreaks is the set of edges that makes one strongly connected
+// component acyclic. Exact is false when the set was found heuristically and
+// may not be minimal; CountsTruncated is set when cycle enumeration hit the
+// --scc-threshold, so cycle counts are lower bounds.
+type componentBreaks struct {
+	Members         []string     `json:"members"`
+	Exact           bool         `json:"exact"`
+	CountsTruncated bool         `json:"countsTruncated,omitempty"`
+	Edges           []cycleBreak `json:"edges"`
+}
+
+// suggestCycleBreaks computes a small feedback arc set for every strongly
+// connected component of graph. cycleLimit bounds the number of cycles
+// enumerated per component to rank the edges (0 = no limit).
+func suggestCycleBreaks(graph map[string][]string, cycleLimit int) []componentBreaks {
+	result := []componentBreaks{}
+	for _, component := range findCycleComponents(graph) {
+		memberSet := map[string]bool{}
+		for _, m := range component.Members {
+			memberSet[m] = true
+		}
+		subgraph := map[string][]string{}
+		for _, m := range component.Members {
+			for _, to := range graph[m] {
+				if memberSet[to] {
+					subgraph[m] = append(subgraph[m], to)
+				}
+			}
+		}
+
+		var edges [][2]string
+		exact := len(component.Members) <= feedbackArcExactMaxNodes
+		if exact {
+			edges = exactFeedbackArcSet(component.Members, subgraph)
+		} else {
+			edges = greedyFeedbackArcSet(component.Members, subgraph)
+		}
+
+		cycles, truncated := findCyclesWithLimit(subgraph, 0, cycleLimit)
+		counts := edgeCycleCounts(cycles)
+		breaks := componentBreaks{
+			Members:         component.Members,
+			Exact:           exact,
+			CountsTruncated: truncated,
+		}
+		for _, e := range edges {
+			breaks.Edges = append(breaks.Edges, cycleBreak{From: e[0], To: e[1], CycleCount: counts[e]})
+		}
+		sort.Slice(breaks.Edges, func(i, j int) bool {
+			a, b := breaks.Edges[i], breaks.Edges[j]
+			if a.CycleCount != b.CycleCount {
+				return a.CycleCount > b.CycleCount
+			}
+			if a.From != b.From {
+				return a.From < b.From
+			}
+			return a.To < b.To
+		})
+		result = append(result, breaks)
+	}
+	return result
+}
+
+// edgeCycleCounts returns the number of cycles each edge participates in.
+func edgeCycleCounts(cycles []Chain) map[[2]string]int {
+	counts := map[[2]string]int{}
+	for _, cycle := range cycles {
+		for i := 0; i+1 < len(cycle); i++ {
+			counts[[2]string{cycle[i], cycle[i+1]}]++
+		}
+	}
+	return counts
+}
+
+// exactFeedbackArcSet finds a minimum set of edges whose removal makes graph
+// acyclic. Every acyclic subgraph follows some ordering of the modules, so
+// this is a dynamic program over subsets of modules that picks the ordering
+// with the fewest edges pointing backwards. Self-loops are always included.
+func exactFeedbackArcSet(members []string, graph map[string][]string) [][2]string {
+	n := len(members)
+	index := map[string]int{}
+	for i, m := range members {
+		index[m] = i
+	}
+	outMask := make([]uint32, n)
+	for i, m := range members {
+		for _, to := range graph[m] {
+			if j := index[to]; j != i {
+				outMask[i] |= 1 << uint(j)
+			}
+		}
+	}
+
+	// cost[S] is the fewest backward edges among orderings that place the
+	// modules in S first; choice[S] is the last module placed.
+	full := uint32(1)<<uint(n) - 1
+	cost := make([]int, full+1)
+	choice := make([]int8, full+1)
+	for s := uint32(1); s <= full; s++ {
+		cost[s] = -1
+		for v := 0; v < n; v++ {
+			if s&(1<<uint(v)) == 0 {
+				continue
+			}
+			prev := s &^ (1 << uint(v))
+			c := cost[prev] + bits.OnesCount32(outMask[v]&prev)
+			if cost[s] < 0 || c < cost[s] {
+				cost[s], choice[s] = c, int8(v)
+			}
+		}
+	}
+
+	position := make([]int, n)
+	for s, p := full, n-1; s != 0; p-- {
+		v := int(choice[s])
+		position[v] = p
+		s &^= 1 << uint(v)
+	}
+	return backwardEdges(members, graph, func(m string) int { return position[index[m]] })
+}
+
+// greedyFeedbackArcSet orders the modules with the Eades-Lin-Smyth heuristic
+// (sinks last, sources first, otherwise the module with the largest
+// out-degree minus in-degree first), takes the edges pointing backwards and
+// then puts back every edge that does not close a cycle.
+func greedyFeedbackArcSet(members []string, graph map[string][]string) [][2]string {
+	out := map[string]map[string]bool{}
+	in := map[string]map[string]bool{}
+	for _, m := range members {
+		out[m], in[m] = map[string]bool{}, map[string]bool{}
+	}
+	for _, m := range members {
+		for _, to := range graph[m] {
+			if to != m {
+				out[m][to] = true
+				in[to][m] = true
+			}
+		}
+	}
+	remaining := map[string]bool{}
+	for _, m := range members {
+		remaining[m] = true
+	}
+	remove := func(m string) {
+		delete(remaining, m)
+		for to := range out[m] {
+			delete(in[to], m)
+		}
+		for from := range in[m] {
+			delete(out[from], m)
+		}
+	}
+
+	var front, back []string
+	for len(remaining) > 0 {
+		progress := true
+		for progress {
+			progress = false
+			for _, m := range members {
+				if !remaining[m] {
+					continue
+				}
+				if len(out[m]) == 0 {
+					back = append(back, m)
+					remove(m)
+					progress = true
+				} else if len(in[m]) == 0 {
+					front = append(front, m)
+					remove(m)
+					progress = true
+				}
+			}
+		}
+		best, bestDelta := "", 0
+		for _, m := range members {
+			if !remaining[m] {
+				continue
+			}
+			if delta := len(out[m]) - len(in[m]); best == "" || delta > bestDelta {
+				best, bestDelta = m, delta
+			}
+		}
+		if best != "" {
+			front = append(front, best)
+			remove(best)
+		}
+	}
+
+	position := map[string]int{}
+	for i, m := range front {
+		position[m] = i
+	}
+	for i, m := range back {
+		position[m] = len(members) - 1 - i
+	}
+	removed := backwardEdges(members, graph, func(m string) int { return position[m] })
+
+	// Put back edges that turned out to be unnecessary.
+	kept := map[string][]string{}
+	removedSet := map[[2]string]bool{}
+	for _, e := range removed {
+		removedSet[e] = true
+	}
+	for _, m := range members {
+		for _, to := range graph[m] {
+			if !removedSet[[2]string{m, to}] {
+				kept[m] = append(kept[m], to)
+			}
+		}
+	}
+	var result [][2]string
+	for _, e := range removed {
+		if e[0] != e[1] && !reachableFrom([]string{e[1]}, kept)[e[0]] {
+			kept[e[0]] = append(kept[e[0]], e[1])
+			continue
+		}
+		result = append(result, e)
+	}
+	return result
+}
+
+// backwardEdges returns the edges of graph that do not go from an earlier
+// to a later position, including self-loops.
+func backwardEdges(members []string, graph map[string][]string, position func(string) int) [][2]string {
+	var edges [][2]string
+	for _, m := range members {
+		for _, to := range graph[m] {
+			if position(to) <= position(m) {
+				edges = append(edges, [2]string{m, to})
+			}
+		}
+	}
+	return edges
+}
+
+func printCycleBreaks(components []componentBreaks) {
+	total := 0
+	for _, c := range components {
+		total += len(c.Edges)
+	}
+	if len(components) == 0 {
+		fmt.Println("No cycles found.")
+		return
+	}
+	fmt.Printf("Removing these %d requirements makes the graph acyclic:\n", total)
+	for i, c := range components {
+		method := "minimal"
+		if !c.Exact {
+			method = "heuristic"
+		}
+		fmt.Printf("\nComponent %d (%d modules, %s):\n", i+1, len(c.Members), method)
+		for _, e := range c.Edges {
+			countSuffix := ""
+			if c.CountsTruncated {
+				countSuffix = "+"
+			}
+			fmt.Printf("- %s -> %s (in %d%s cycles)\n", e.From, e.To, e.CycleCount, countSuffix)
+		}
+	}
+}
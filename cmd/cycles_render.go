@@ -0,0 +1,275 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// cycleMutualColor highlights the edges of two-node cycles.
+const cycleMutualColor = "#c62828"
+
+// cycleGroup is one box of the --dot, --svg and --mermaid output of cycles:
+// a strongly connected component, or a single cycle with --per-cycle.
+type cycleGroup struct {
+	Title string
+	// Prefix is prepended to node IDs, since a module can appear in several
+	// groups with --per-cycle.
+	Prefix string
+	Nodes  []string
+	Edges  []cycleGroupEdge
+	// Truncated is set when cycle enumeration stopped early, so the counts
+	// are lower bounds.
+	Truncated bool
+}
+
+// cycleGroupEdge is an edge labelled with the number of cycles it is part
+// of. Mutual is set for the edges of two-node cycles.
+type cycleGroupEdge struct {
+	From, To string
+	Count    int
+	Mutual   bool
+}
+
+// buildCycleGroups groups the edges of cycles by strongly connected
+// component of graph, largest first, or by cycle if perCycle is set. If
+// truncated is set, cycles is only part of the cycles of graph: a component
+// then keeps all of its edges, including those no cycle found so far uses.
+func buildCycleGroups(graph map[string][]string, cycles []Chain, perCycle, truncated bool) []cycleGroup {
+	counts := edgeCycleCounts(cycles)
+	mutual := map[[2]string]bool{}
+	for _, pair := range summarizeCycles(cycles, 0).TwoNodeCycles {
+		mutual[[2]string{pair[0], pair[1]}] = true
+		mutual[[2]string{pair[1], pair[0]}] = true
+	}
+	edge := func(from, to string) cycleGroupEdge {
+		e := [2]string{from, to}
+		return cycleGroupEdge{From: from, To: to, Count: counts[e], Mutual: mutual[e]}
+	}
+
+	groups := []cycleGroup{}
+	if perCycle {
+		for i, cycle := range cycles {
+			g := cycleGroup{
+				Title:  fmt.Sprintf("Cycle %d (length %d)", i+1, len(cycle)-1),
+				Prefix: fmt.Sprintf("c%d:", i+1),
+				// Each group is a whole cycle, but the counts are partial.
+				Truncated: truncated,
+			}
+			seen := map[string]bool{}
+			for j := 0; j+1 < len(cycle); j++ {
+				if !seen[cycle[j]] {
+					seen[cycle[j]] = true
+					g.Nodes = append(g.Nodes, cycle[j])
+				}
+				g.Edges = append(g.Edges, edge(cycle[j], cycle[j+1]))
+			}
+			groups = append(groups, g)
+		}
+		return groups
+	}
+
+	cyclesByStart := map[string]int{}
+	for _, cycle := range cycles {
+		cyclesByStart[cycle[0]]++
+	}
+	for _, component := range stronglyConnectedComponents(graph) {
+		memberSet := map[string]bool{}
+		for _, m := range component {
+			memberSet[m] = true
+		}
+		g := cycleGroup{Truncated: truncated}
+		nodeSet := map[string]bool{}
+		componentCycles := 0
+		for _, m := range component {
+			componentCycles += cyclesByStart[m]
+			for _, to := range graph[m] {
+				if !memberSet[to] || (counts[[2]string{m, to}] == 0 && !truncated) {
+					continue
+				}
+				g.Edges = append(g.Edges, edge(m, to))
+				nodeSet[m], nodeSet[to] = true, true
+			}
+		}
+		if len(g.Edges) == 0 {
+			continue
+		}
+		for _, m := range component {
+			if nodeSet[m] {
+				g.Nodes = append(g.Nodes, m)
+			}
+		}
+		if truncated {
+			g.Title = fmt.Sprintf("%d modules, at least %d cycles", len(g.Nodes), componentCycles)
+		} else {
+			g.Title = fmt.Sprintf("%d modules, %d cycles", len(g.Nodes), componentCycles)
+		}
+		groups = append(groups, g)
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		if len(groups[i].Nodes) != len(groups[j].Nodes) {
+			return len(groups[i].Nodes) > len(groups[j].Nodes)
+		}
+		return groups[i].Nodes[0] < groups[j].Nodes[0]
+	})
+	for i := range groups {
+		groups[i].Title = fmt.Sprintf("Component %d: %s", i+1, groups[i].Title)
+	}
+	return groups
+}
+
+// edgeLabel returns the label of e: its cycle count, followed by "+" if the
+// count is a lower bound.
+func (g cycleGroup) edgeLabel(e cycleGroupEdge) string {
+	if g.Truncated {
+		return strconv.Itoa(e.Count) + "+"
+	}
+	return strconv.Itoa(e.Count)
+}
+
+// cycleGroupsTitle returns the title of the whole cycle diagram.
+func cycleGroupsTitle(groups []cycleGroup) string {
+	for _, g := range groups {
+		if g.Truncated {
+			return "Dependency cycles (truncated)"
+		}
+	}
+	return "Dependency cycles"
+}
+
+// mutualNodes returns the modules of g that are part of a two-node cycle.
+func (g cycleGroup) mutualNodes() map[string]bool {
+	nodes := map[string]bool{}
+	for _, e := range g.Edges {
+		if e.Mutual {
+			nodes[e.From], nodes[e.To] = true, true
+		}
+	}
+	return nodes
+}
+
+// getDOTForCycles renders every group as a Graphviz cluster. Edges are
+// labelled with their cycle count and two-node cycles are drawn in red.
+func getDOTForCycles(groups []cycleGroup) string {
+	var b strings.Builder
+	b.WriteString("strict digraph {\n")
+	fmt.Fprintf(&b, "graph [overlap=false, label=\"%s\", labelloc=t];\n", cycleGroupsTitle(groups))
+	b.WriteString("node [shape=box, style=filled, fillcolor=white];\n")
+	for i, g := range groups {
+		mutualNodes := g.mutualNodes()
+		fmt.Fprintf(&b, "subgraph cluster_%d {\nlabel=\"%s\";\nstyle=\"rounded,dashed\";\ncolor=\"gray50\";\n", i, g.Title)
+		for _, m := range g.Nodes {
+			fill := "white"
+			if mutualNodes[m] {
+				fill = "#ffcdd2"
+			}
+			fmt.Fprintf(&b, "\"%s%s\" [label=\"%s\", fillcolor=\"%s\"];\n", g.Prefix, m, m, fill)
+		}
+		b.WriteString("}\n")
+		for _, e := range g.Edges {
+			attrs := fmt.Sprintf("label=\"%s\"", g.edgeLabel(e))
+			if e.Mutual {
+				attrs += fmt.Sprintf(", color=\"%s\", fontcolor=\"%s\", penwidth=2", cycleMutualColor, cycleMutualColor)
+			}
+			fmt.Fprintf(&b, "\"%s%s\" -> \"%s%s\" [%s];\n", g.Prefix, e.From, g.Prefix, e.To, attrs)
+		}
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// getMermaidForCycles renders every group as a Mermaid subgraph.
+func getMermaidForCycles(groups []cycleGroup) string {
+	m := newMermaidGraph(cycleGroupsTitle(groups), "TD")
+	for _, g := range groups {
+		mutualNodes := g.mutualNodes()
+		ids := make([]string, 0, len(g.Nodes))
+		for _, node := range g.Nodes {
+			class := ""
+			if mutualNodes[node] {
+				class = "mutual"
+			}
+			m.AddNode(g.Prefix+node, node, class)
+			ids = append(ids, g.Prefix+node)
+		}
+		m.AddSubgraph(g.Title, ids)
+		for _, e := range g.Edges {
+			me := mermaidEdge{From: g.Prefix + e.From, To: g.Prefix + e.To, Style: mermaidSolid, Label: g.edgeLabel(e)}
+			if e.Mutual {
+				me.Style, me.Color = mermaidThick, cycleMutualColor
+			}
+			m.AddEdge(me)
+		}
+	}
+	return m.String()
+}
+
+// getSVGForCycles renders every group as its own diagram, stacked
+// vertically in a single SVG document.
+func getSVGForCycles(groups []cycleGroup, mainModules []string) string {
+	if len(groups) == 0 {
+		return `<svg xmlns="http://www.w3.org/2000/svg" width="400" height="80">
+<text x="200" y="40" text-anchor="middle" font-family="sans-serif" font-size="14">No dependency cycles found</text>
+</svg>
+`
+	}
+	diagrams := make([]svgDiagram, 0, len(groups))
+	for i, g := range groups {
+		g := g
+		edges := make([]svgEdge, 0, len(g.Edges))
+		byEdge := map[svgEdge]cycleGroupEdge{}
+		for _, e := range g.Edges {
+			se := svgEdge{From: e.From, To: e.To}
+			edges = append(edges, se)
+			byEdge[se] = e
+		}
+		d := svgDiagram{
+			Title:  g.Title,
+			Nodes:  g.Nodes,
+			Edges:  edges,
+			Layout: layoutOptions{Roots: mainModules, MaxWidth: svgMaxWidth},
+			NodeStyle: func(node string) svgNodeStyle {
+				return svgNodeStyle{
+					Label: abbreviateModule(node, mainModules),
+					Color: classifyNodeColor(node, "", mainModules),
+				}
+			},
+			EdgeStyle: func(se svgEdge, layerDiff int) svgEdgeStyle {
+				e := byEdge[se]
+				s := svgEdgeStyle{Label: g.edgeLabel(e)}
+				if e.Mutual {
+					s.Stroke, s.Width = cycleMutualColor, "2.2"
+				}
+				return s
+			},
+		}
+		if i == 0 {
+			d.Subtitle = fmt.Sprintf("%d groups; edges are labelled with the number of cycles they are part of, two-node cycles in red", len(groups))
+			if g.Truncated {
+				d.Subtitle = fmt.Sprintf("%d groups; enumeration was truncated, so edges are labelled with the number of cycles found so far, two-node cycles in red", len(groups))
+			}
+			d.Legend = svgDefaultLegend[:3]
+		} else {
+			d.Layout.Top = 60
+		}
+		diagrams = append(diagrams, d)
+	}
+	return renderSVGStack(diagrams)
+}
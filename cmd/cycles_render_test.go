@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func cycleRenderGraph() map[string][]string {
+	return map[string][]string{
+		"main": {"A", "X"},
+		"A":    {"B"},
+		"B":    {"A", "C"},
+		"C":    {"A"},
+		"X":    {"Y"},
+		"Y":    {"X"},
+	}
+}
+
+func TestBuildCycleGroups(t *testing.T) {
+	graph := cycleRenderGraph()
+	cycles := findAllCycles(graph)
+
+	groups := buildCycleGroups(graph, cycles, false, false)
+	if len(groups) != 2 {
+		t.Fatalf("expected 2 components, got %+v", groups)
+	}
+	abc := groups[0]
+	if abc.Title != "Component 1: 3 modules, 2 cycles" || !isSliceSame(abc.Nodes, []string{"A", "B", "C"}) {
+		t.Fatalf("unexpected first group: %+v", abc)
+	}
+	want := map[[2]string]cycleGroupEdge{
+		{"A", "B"}: {From: "A", To: "B", Count: 2, Mutual: true},
+		{"B", "A"}: {From: "B", To: "A", Count: 1, Mutual: true},
+		{"B", "C"}: {From: "B", To: "C", Count: 1},
+		{"C", "A"}: {From: "C", To: "A", Count: 1},
+	}
+	if len(abc.Edges) != len(want) {
+		t.Fatalf("expected %d edges, got %+v", len(want), abc.Edges)
+	}
+	for _, e := range abc.Edges {
+		if want[[2]string{e.From, e.To}] != e {
+			t.Fatalf("unexpected edge %+v", e)
+		}
+	}
+	if groups[1].Title != "Component 2: 2 modules, 1 cycles" {
+		t.Fatalf("unexpected second group: %+v", groups[1])
+	}
+
+	perCycle := buildCycleGroups(graph, cycles, true, false)
+	if len(perCycle) != len(cycles) {
+		t.Fatalf("expected one group per cycle, got %d", len(perCycle))
+	}
+	if perCycle[0].Prefix == perCycle[1].Prefix {
+		t.Fatalf("expected distinct node prefixes, got %q", perCycle[0].Prefix)
+	}
+}
+
+func TestCycleRenderers(t *testing.T) {
+	graph := cycleRenderGraph()
+	groups := buildCycleGroups(graph, findAllCycles(graph), false, false)
+
+	dot := getDOTForCycles(groups)
+	for _, want := range []string{
+		"subgraph cluster_0 {\nlabel=\"Component 1: 3 modules, 2 cycles\";",
+		"\"A\" -> \"B\" [label=\"2\", color=\"#c62828\", fontcolor=\"#c62828\", penwidth=2];",
+		"\"B\" -> \"C\" [label=\"1\"];",
+		"\"A\" [label=\"A\", fillcolor=\"#ffcdd2\"];",
+		"\"C\" [label=\"C\", fillcolor=\"white\"];",
+	} {
+		if !strings.Contains(dot, want) {
+			t.Fatalf("DOT output missing %q:\n%s", want, dot)
+		}
+	}
+
+	mermaid := getMermaidForCycles(groups)
+	for _, want := range []string{
+		"subgraph s0[\"Component 1: 3 modules, 2 cycles\"]",
+		"n0 ==>|2| n1",
+		"classDef mutual",
+	} {
+		if !strings.Contains(mermaid, want) {
+			t.Fatalf("Mermaid output missing %q:\n%s", want, mermaid)
+		}
+	}
+
+	svg := getSVGForCycles(groups, []string{"main"})
+	if err := xml.Unmarshal([]byte(svg), new(interface{})); err != nil {
+		t.Fatalf("SVG is not well-formed: %v", err)
+	}
+	if strings.Count(svg, "<svg") != 1 || strings.Count(svg, "<g transform=") != 2 {
+		t.Fatalf("expected one document with two stacked diagrams:\n%s", svg)
+	}
+	if !strings.Contains(svg, `id="d1m0"`) {
+		t.Fatalf("expected per-diagram marker IDs:\n%s", svg)
+	}
+}
+
+func TestBuildCycleGroupsTruncated(t *testing.T) {
+	graph := cycleRenderGraph()
+	cycles, stoppedBy := findCycles(graph, cycleSearch{MaxCycles: 1})
+	if stoppedBy != cycleStopMaxCycles {
+		t.Fatalf("expected the search to stop at --max-cycles, got %q", stoppedBy)
+	}
+
+	groups := buildCycleGroups(graph, cycles, false, true)
+	edges := 0
+	for _, g := range groups {
+		if !g.Truncated || !strings.Contains(g.Title, "at least") {
+			t.Fatalf("expected a truncated group, got %+v", g)
+		}
+		edges += len(g.Edges)
+	}
+	// Every edge within a component is drawn, even those no cycle found so
+	// far uses.
+	if edges != 6 {
+		t.Fatalf("expected all 6 component edges, got %+v", groups)
+	}
+
+	dot := getDOTForCycles(groups)
+	if !strings.Contains(dot, "label=\"Dependency cycles (truncated)\"") || !strings.Contains(dot, "[label=\"0+\"]") {
+		t.Fatalf("expected truncated counts in DOT output:\n%s", dot)
+	}
+	if mermaid := getMermaidForCycles(groups); !strings.Contains(mermaid, "|0+|") {
+		t.Fatalf("expected truncated counts in Mermaid output:\n%s", mermaid)
+	}
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// defaultSCCThreshold is the number of cycles above which --summary reports
+// strongly connected components instead of enumerating every cycle.
+const defaultSCCThreshold = 10000
+
+// cycleComponent is a strongly connected component that contains at least one
+// cycle, as reported by cycles --scc.
+type cycleComponent struct {
+	Size           int           `json:"size"`
+	Members        []string      `json:"members"`
+	InternalEdges  int           `json:"internalEdges"`
+	ShortestCycles []memberCycle `json:"shortestCycles"`
+}
+
+// memberCycle is the shortest cycle through Module, closed like the cycles
+// returned by findAllCycles (first == last).
+type memberCycle struct {
+	Module string `json:"module"`
+	Cycle  Chain  `json:"cycle"`
+}
+
+// findCycleComponents returns the strongly connected components of graph
+// that contain a cycle: those with more than one module, or a single module
+// depending on itself. Components are sorted by size, largest first.
+func findCycleComponents(graph map[string][]string) []cycleComponent {
+	components := []cycleComponent{}
+	for _, members := range stronglyConnectedComponents(graph) {
+		memberSet := map[string]bool{}
+		for _, m := range members {
+			memberSet[m] = true
+		}
+		internalEdges := 0
+		for _, m := range members {
+			for _, to := range graph[m] {
+				if memberSet[to] {
+					internalEdges++
+				}
+			}
+		}
+		if len(members) == 1 && internalEdges == 0 {
+			continue
+		}
+		component := cycleComponent{
+			Size:          len(members),
+			Members:       members,
+			InternalEdges: internalEdges,
+		}
+		for _, m := range members {
+			component.ShortestCycles = append(component.ShortestCycles, memberCycle{
+				Module: m,
+				Cycle:  shortestCycleThrough(m, graph, memberSet),
+			})
+		}
+		components = append(components, component)
+	}
+	sort.Slice(components, func(i, j int) bool {
+		if components[i].Size != components[j].Size {
+			return components[i].Size > components[j].Size
+		}
+		return components[i].Members[0] < components[j].Members[0]
+	})
+	return components
+}
+
+// limitCycleComponents keeps the shortest cycles of at most maxLength
+// modules, and drops the components left without any. A module is on a cycle
+// within the limit exactly when its shortest cycle is, so the components
+// kept are the ones with such a cycle.
+func limitCycleComponents(components []cycleComponent, maxLength int) []cycleComponent {
+	limited := []cycleComponent{}
+	for _, c := range components {
+		var kept []memberCycle
+		for _, mc := range c.ShortestCycles {
+			if len(mc.Cycle)-1 <= maxLength {
+				kept = append(kept, mc)
+			}
+		}
+		if len(kept) > 0 {
+			c.ShortestCycles = kept
+			limited = append(limited, c)
+		}
+	}
+	return limited
+}
+
+// shortestCycleThrough runs a breadth-first search from module, restricted to
+// the modules in component, and returns the shortest path back to module.
+func shortestCycleThrough(module string, graph map[string][]string, component map[string]bool) Chain {
+	parent := map[string]string{}
+	queue := []string{module}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[current] {
+			if !component[next] {
+				continue
+			}
+			if next == module {
+				cycle := Chain{module}
+				for n := current; n != module; n = parent[n] {
+					cycle = append(cycle, n)
+				}
+				cycle = append(cycle, module)
+				// The path was collected backwards; keep module at both ends.
+				for i, j := 1, len(cycle)-2; i < j; i, j = i+1, j-1 {
+					cycle[i], cycle[j] = cycle[j], cycle[i]
+				}
+				return cycle
+			}
+			if _, seen := parent[next]; !seen {
+				parent[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+func printCycleComponents(components []cycleComponent) {
+	fmt.Printf("Strongly connected components with cycles: %d\n", len(components))
+	for i, c := range components {
+		fmt.Printf("\nComponent %d: %d modules, %d internal edges\n", i+1, c.Size, c.InternalEdges)
+		fmt.Println("Shortest cycle through each member:")
+		for _, mc := range c.ShortestCycles {
+			fmt.Printf("- %s: %s\n", mc.Module, strings.Join(mc.Cycle, " -> "))
+		}
+	}
+}
@@ -1,6 +1,12 @@
 package cmd
 
-import "testing"
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
 
 func TestFindAllCyclesWithMaxLength(t *testing.T) {
 	graph := map[string][]string{
@@ -111,3 +117,269 @@ func TestDiffSummary(t *testing.T) {
 		t.Fatalf("expected VersionChangesCount 2, got %d", summary.VersionChangesCount)
 	}
 }
+
+func TestFindCycleComponents(t *testing.T) {
+	graph := map[string][]string{
+		"A": {"B"},
+		"B": {"C", "D"},
+		"C": {"A"},
+		"D": {"E"},
+		"E": {"E"},
+		"F": {"A"},
+	}
+
+	components := findCycleComponents(graph)
+	if len(components) != 2 {
+		t.Fatalf("expected 2 components, got %d (%v)", len(components), components)
+	}
+	abc := components[0]
+	if abc.Size != 3 || !isSliceSame(abc.Members, []string{"A", "B", "C"}) || abc.InternalEdges != 3 {
+		t.Fatalf("unexpected first component: %+v", abc)
+	}
+	want := map[string]Chain{
+		"A": {"A", "B", "C", "A"},
+		"B": {"B", "C", "A", "B"},
+		"C": {"C", "A", "B", "C"},
+	}
+	for _, mc := range abc.ShortestCycles {
+		if !isSliceSame(mc.Cycle, want[mc.Module]) {
+			t.Fatalf("shortest cycle through %s = %v, want %v", mc.Module, mc.Cycle, want[mc.Module])
+		}
+	}
+	self := components[1]
+	if self.Size != 1 || self.InternalEdges != 1 || !isSliceSame(self.ShortestCycles[0].Cycle, Chain{"E", "E"}) {
+		t.Fatalf("unexpected self-loop component: %+v", self)
+	}
+}
+
+func TestShortestCycleThroughPicksShortest(t *testing.T) {
+	graph := map[string][]string{
+		"A": {"B", "D"},
+		"B": {"C"},
+		"C": {"A"},
+		"D": {"A"},
+	}
+	component := map[string]bool{"A": true, "B": true, "C": true, "D": true}
+	if got := shortestCycleThrough("A", graph, component); !isSliceSame(got, Chain{"A", "D", "A"}) {
+		t.Fatalf("expected A -> D -> A, got %v", got)
+	}
+}
+
+func TestFindCyclesWithLimit(t *testing.T) {
+	graph := map[string][]string{
+		"A": {"B", "C"},
+		"B": {"A", "C"},
+		"C": {"A"},
+	}
+
+	cycles, truncated := findCyclesWithLimit(graph, 0, 3)
+	if truncated || len(cycles) != 3 {
+		t.Fatalf("expected all 3 cycles without truncation, got %d (truncated=%v)", len(cycles), truncated)
+	}
+	cycles, truncated = findCyclesWithLimit(graph, 0, 1)
+	if !truncated || len(cycles) != 2 {
+		t.Fatalf("expected truncation after 2 cycles, got %d (truncated=%v)", len(cycles), truncated)
+	}
+}
+
+func isAcyclicWithout(graph map[string][]string, removed []cycleBreak) bool {
+	skip := map[[2]string]bool{}
+	for _, e := range removed {
+		skip[[2]string{e.From, e.To}] = true
+	}
+	kept := map[string][]string{}
+	for from, tos := range graph {
+		for _, to := range tos {
+			if !skip[[2]string{from, to}] {
+				kept[from] = append(kept[from], to)
+			}
+		}
+	}
+	return len(findAllCycles(kept)) == 0
+}
+
+func TestSuggestCycleBreaksExact(t *testing.T) {
+	// Both cycles go through A -> B, so removing it alone is enough.
+	graph := map[string][]string{
+		"A": {"B"},
+		"B": {"C", "D"},
+		"C": {"A"},
+		"D": {"A"},
+		"E": {"E"},
+	}
+
+	breaks := suggestCycleBreaks(graph, 0)
+	if len(breaks) != 2 {
+		t.Fatalf("expected 2 components, got %v", breaks)
+	}
+	abcd := breaks[0]
+	if !abcd.Exact || len(abcd.Edges) != 1 {
+		t.Fatalf("expected a single exact break, got %+v", abcd)
+	}
+	if e := abcd.Edges[0]; e.From != "A" || e.To != "B" || e.CycleCount != 2 {
+		t.Fatalf("expected A -> B in 2 cycles, got %+v", e)
+	}
+	if e := breaks[1].Edges; len(e) != 1 || e[0].From != "E" || e[0].To != "E" {
+		t.Fatalf("expected the self-loop to be removed, got %+v", e)
+	}
+	if removed := append(abcd.Edges, breaks[1].Edges...); !isAcyclicWithout(graph, removed) {
+		t.Fatalf("graph still has cycles after removing %v", removed)
+	}
+}
+
+func TestGreedyFeedbackArcSet(t *testing.T) {
+	// A ring of 20 modules with shortcuts back to M00. Every cycle runs
+	// through M00 -> ... -> M04, so a single edge is enough.
+	graph := map[string][]string{}
+	var members []string
+	for i := 0; i < 20; i++ {
+		from := fmt.Sprintf("M%02d", i)
+		to := fmt.Sprintf("M%02d", (i+1)%20)
+		members = append(members, from)
+		graph[from] = append(graph[from], to)
+		if i%5 == 4 && i != 19 {
+			graph[from] = append(graph[from], "M00")
+		}
+	}
+
+	var removed []cycleBreak
+	for _, e := range greedyFeedbackArcSet(members, graph) {
+		removed = append(removed, cycleBreak{From: e[0], To: e[1]})
+	}
+	if !isAcyclicWithout(graph, removed) {
+		t.Fatalf("graph still has cycles after removing %v", removed)
+	}
+	if len(removed) != 1 {
+		t.Fatalf("expected a single edge to be removed, got %v", removed)
+	}
+
+	breaks := suggestCycleBreaks(graph, 0)
+	if len(breaks) != 1 || breaks[0].Exact {
+		t.Fatalf("expected one heuristic component, got %+v", breaks)
+	}
+}
+
+func TestFindCyclesStreamsAndStops(t *testing.T) {
+	graph := map[string][]string{
+		"A": {"B", "C"},
+		"B": {"A", "C"},
+		"C": {"A"},
+	}
+
+	var streamed []Chain
+	cycles, stoppedBy := findCycles(graph, cycleSearch{OnCycle: func(c Chain) { streamed = append(streamed, c) }})
+	if stoppedBy != "" || len(cycles) != 0 || len(streamed) != 3 {
+		t.Fatalf("expected 3 streamed cycles, got %v streamed, %v returned, stoppedBy=%q", streamed, cycles, stoppedBy)
+	}
+
+	cycles, stoppedBy = findCycles(graph, cycleSearch{MaxCycles: 2})
+	if stoppedBy != cycleStopMaxCycles || len(cycles) != 2 {
+		t.Fatalf("expected 2 cycles stopped by max-cycles, got %v (%q)", cycles, stoppedBy)
+	}
+}
+
+func TestFindCyclesTimeout(t *testing.T) {
+	// A complete graph on 9 modules has far more cycles than can be
+	// enumerated before the deadline check.
+	graph := map[string][]string{}
+	for i := 0; i < 9; i++ {
+		for j := 0; j < 9; j++ {
+			if i != j {
+				graph[fmt.Sprint(i)] = append(graph[fmt.Sprint(i)], fmt.Sprint(j))
+			}
+		}
+	}
+	builder := newCycleSummaryBuilder()
+	_, stoppedBy := findCycles(graph, cycleSearch{Deadline: time.Now().Add(-time.Second), OnCycle: builder.add})
+	if stoppedBy != cycleStopTimeout {
+		t.Fatalf("expected the search to time out, got %q", stoppedBy)
+	}
+	if s := builder.summary(3); s.TotalCycles == 0 || len(s.TopParticipants) != 3 {
+		t.Fatalf("expected a partial summary, got %+v", s)
+	}
+}
+
+func TestCycleSummaryBuilderMatchesSummarizeCycles(t *testing.T) {
+	cycles := []Chain{
+		{"A", "B", "A"},
+		{"B", "C", "B"},
+		{"A", "C", "D", "A"},
+	}
+	builder := newCycleSummaryBuilder()
+	for _, c := range cycles {
+		builder.add(c)
+	}
+	got, want := builder.summary(2), summarizeCycles(cycles, 2)
+	if got.TotalCycles != want.TotalCycles || len(got.TwoNodeCycles) != len(want.TwoNodeCycles) ||
+		got.ByLength["2"] != want.ByLength["2"] || got.TopParticipants[0] != want.TopParticipants[0] {
+		t.Fatalf("builder summary %+v differs from %+v", got, want)
+	}
+}
+
+func TestStreamCyclesJSONLines(t *testing.T) {
+	graph := map[string][]string{
+		"A": {"B", "C"},
+		"B": {"A", "C"},
+		"C": {"A"},
+	}
+	oldJSON := jsonOutputCycles
+	jsonOutputCycles = true
+	defer func() { jsonOutputCycles = oldJSON }()
+
+	out := captureStdout(t, func() {
+		if err := streamCycles(graph, cycleSearch{MaxCycles: 2}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 2 cycle lines and a final line, got:\n%s", out)
+	}
+	if lines[0] != `{"cycle":["A","B","A"]}` {
+		t.Fatalf("unexpected first line %q", lines[0])
+	}
+	if lines[2] != `{"totalCycles":2,"truncated":true,"truncatedBy":"max-cycles"}` {
+		t.Fatalf("unexpected final line %q", lines[2])
+	}
+}
+
+func TestCycleSummaryFallbackKeepsMaxLength(t *testing.T) {
+	// A <-> B and B <-> A2 are the cycles of at most 2 modules, which is
+	// more than the threshold; C -> D -> E -> C is longer.
+	graph := map[string][]string{
+		"A":  {"B", "C"},
+		"B":  {"A", "A2"},
+		"A2": {"B"},
+		"C":  {"D"},
+		"D":  {"E"},
+		"E":  {"C"},
+	}
+	oldThreshold, oldJSON := sccThreshold, jsonOutputCycles
+	sccThreshold, jsonOutputCycles = 1, true
+	defer func() { sccThreshold, jsonOutputCycles = oldThreshold, oldJSON }()
+
+	out := captureStdout(t, func() {
+		if err := outputCycleSummary(graph, cycleSearch{MaxLength: 2}); err != nil {
+			t.Fatal(err)
+		}
+	})
+	var report struct {
+		Components        []cycleComponent `json:"components"`
+		ThresholdExceeded bool             `json:"thresholdExceeded"`
+		MaxLength         int              `json:"maxLength"`
+	}
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if !report.ThresholdExceeded || report.MaxLength != 2 {
+		t.Fatalf("unexpected report header: %+v", report)
+	}
+	if len(report.Components) != 1 || !isSliceSame(report.Components[0].Members, []string{"A", "A2", "B"}) {
+		t.Fatalf("expected only the A, A2, B component, got %+v", report.Components)
+	}
+	for _, mc := range report.Components[0].ShortestCycles {
+		if len(mc.Cycle)-1 > 2 {
+			t.Fatalf("cycle %v is longer than --max-length", mc.Cycle)
+		}
+	}
+}
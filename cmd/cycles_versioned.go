@@ -0,0 +1,163 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Status of a cycle reported by cycles --versioned.
+const (
+	cycleLive       = "live"
+	cycleHistorical = "historical"
+)
+
+// versionedCycle is a cycle of the module graph built from every version in
+// "go mod graph". It is live if the selected version of every module in it
+// requires the next one, and historical if some requirement only comes from
+// superseded versions.
+type versionedCycle struct {
+	Cycle           Chain            `json:"cycle"`
+	Status          string           `json:"status"`
+	HistoricalEdges []historicalEdge `json:"historicalEdges,omitempty"`
+}
+
+// versionedCycleReport is the --json output of cycles --versioned.
+type versionedCycleReport struct {
+	Cycles      []versionedCycle `json:"cycles"`
+	Truncated   bool             `json:"truncated,omitempty"`
+	TruncatedBy string           `json:"truncatedBy,omitempty"`
+}
+
+// historicalEdge is a requirement that the selected version of From no
+// longer has; Versions lists the superseded versions of From that have it.
+type historicalEdge struct {
+	From     string   `json:"from"`
+	To       string   `json:"to"`
+	Selected string   `json:"selected"`
+	Versions []string `json:"versions"`
+}
+
+// parseVersionedGraph parses "go mod graph" output into the requirements of
+// every module@version. Toolchain lines are skipped.
+func parseVersionedGraph(goModGraphOutput string) map[module][]module {
+	graph := map[module][]module{}
+	scanner := bufio.NewScanner(strings.NewReader(goModGraphOutput))
+	for scanner.Scan() {
+		words := strings.Fields(scanner.Text())
+		if len(words) < 2 {
+			continue
+		}
+		lhs, rhs := parseModule(words[0]), parseModule(words[1])
+		if lhs.name == "go" || strings.HasPrefix(lhs.name, "toolchain") ||
+			rhs.name == "go" || strings.HasPrefix(rhs.name, "toolchain") {
+			continue
+		}
+		graph[lhs] = append(graph[lhs], rhs)
+	}
+	return graph
+}
+
+// findVersionedCycles collapses every version of the versioned graph by
+// module name, restricted to the modules of the build (the keys of
+// selected), finds the cycles of the result within the bounds of search and
+// labels them live or historical against the selected versions. Modules
+// matching exclude are left out. It also returns why the enumeration
+// stopped early ("" if it completed).
+func findVersionedCycles(versioned map[module][]module, selected map[string]string, exclude []string, search cycleSearch) ([]versionedCycle, string) {
+	inBuild := func(name string) bool {
+		_, ok := selected[name]
+		return ok && !moduleExcluded(name, exclude)
+	}
+	// requiredBy[from][to] lists the versions of from that require to.
+	requiredBy := map[string]map[string][]string{}
+	for lhs, rhss := range versioned {
+		if !inBuild(lhs.name) {
+			continue
+		}
+		for _, rhs := range rhss {
+			if rhs.name == lhs.name || !inBuild(rhs.name) {
+				continue
+			}
+			if requiredBy[lhs.name] == nil {
+				requiredBy[lhs.name] = map[string][]string{}
+			}
+			versions := requiredBy[lhs.name][rhs.name]
+			if !contains(versions, lhs.version) {
+				requiredBy[lhs.name][rhs.name] = append(versions, lhs.version)
+			}
+		}
+	}
+
+	collapsed := map[string][]string{}
+	for from, tos := range requiredBy {
+		for to := range tos {
+			collapsed[from] = append(collapsed[from], to)
+		}
+		sort.Strings(collapsed[from])
+	}
+
+	result := []versionedCycle{}
+	cycles, stoppedBy := findCycles(collapsed, search)
+	for _, cycle := range cycles {
+		vc := versionedCycle{Cycle: cycle, Status: cycleLive}
+		for i := 0; i+1 < len(cycle); i++ {
+			from, to := cycle[i], cycle[i+1]
+			versions := requiredBy[from][to]
+			if contains(versions, selected[from]) {
+				continue
+			}
+			sorted := append([]string{}, versions...)
+			sort.Slice(sorted, func(a, b int) bool { return versionGreater(sorted[b], sorted[a]) })
+			vc.Status = cycleHistorical
+			vc.HistoricalEdges = append(vc.HistoricalEdges, historicalEdge{
+				From:     from,
+				To:       to,
+				Selected: selected[from],
+				Versions: sorted,
+			})
+		}
+		result = append(result, vc)
+	}
+	return result, stoppedBy
+}
+
+func printVersionedCycles(cycles []versionedCycle, stoppedBy string) {
+	live := 0
+	for _, c := range cycles {
+		if c.Status == cycleLive {
+			live++
+		}
+	}
+	fmt.Printf("Cycles across all module versions: %d (%d live, %d historical)\n", len(cycles), live, len(cycles)-live)
+	for _, c := range cycles {
+		fmt.Printf("\n[%s] %s\n", c.Status, strings.Join(c.Cycle, " -> "))
+		for _, e := range c.HistoricalEdges {
+			versions := make([]string, 0, len(e.Versions))
+			for _, v := range e.Versions {
+				versions = append(versions, e.From+"@"+v)
+			}
+			fmt.Printf("  %s -> %s only required by %s (selected: %s)\n", e.From, e.To, strings.Join(versions, ", "), e.Selected)
+		}
+	}
+	if stoppedBy != "" {
+		fmt.Printf("\n(enumeration truncated by %s after %d cycles)\n", stoppedBy, len(cycles))
+	}
+}
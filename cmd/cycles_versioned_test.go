@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindVersionedCycles(t *testing.T) {
+	goModGraph := `main A@v2.0.0
+main B@v3.0.0
+A@v1.0.0 B@v1.0.0
+A@v1.0.0 D@v1.0.0
+D@v1.0.0 A@v1.0.0
+A@v2.0.0 C@v1.0.0
+B@v3.0.0 A@v1.0.0
+C@v1.0.0 A@v2.0.0
+go@1.22 toolchain@go1.22.0
+`
+	overview := generateGraph(goModGraph, []string{"main"})
+	// D is only required by a superseded version of A, so it is not in the
+	// build and its cycle with A is not reported.
+	if _, ok := overview.Versions["D"]; ok {
+		t.Fatal("D should not be part of the build")
+	}
+	cycles, stoppedBy := findVersionedCycles(parseVersionedGraph(goModGraph), overview.Versions, nil, cycleSearch{})
+	if stoppedBy != "" {
+		t.Fatalf("unexpected truncation by %s", stoppedBy)
+	}
+	if len(cycles) != 2 {
+		t.Fatalf("expected 2 cycles, got %+v", cycles)
+	}
+
+	historical, live := cycles[0], cycles[1]
+	if !isSliceSame(historical.Cycle, Chain{"A", "B", "A"}) || historical.Status != cycleHistorical {
+		t.Fatalf("expected A -> B -> A to be historical, got %+v", historical)
+	}
+	if len(historical.HistoricalEdges) != 1 {
+		t.Fatalf("expected one historical edge, got %+v", historical.HistoricalEdges)
+	}
+	e := historical.HistoricalEdges[0]
+	if e.From != "A" || e.To != "B" || e.Selected != "v2.0.0" || !isSliceSame(e.Versions, []string{"v1.0.0"}) {
+		t.Fatalf("unexpected historical edge: %+v", e)
+	}
+	if !isSliceSame(live.Cycle, Chain{"A", "C", "A"}) || live.Status != cycleLive || len(live.HistoricalEdges) != 0 {
+		t.Fatalf("expected A -> C -> A to be live, got %+v", live)
+	}
+
+	// The collapsed graph of selected versions only has the live cycle.
+	if selected := findAllCycles(overview.Graph); len(selected) != 1 {
+		t.Fatalf("expected one cycle among selected versions, got %v", selected)
+	}
+
+	excluded, _ := findVersionedCycles(parseVersionedGraph(goModGraph), overview.Versions, []string{"C"}, cycleSearch{})
+	if len(excluded) != 1 || excluded[0].Status != cycleHistorical {
+		t.Fatalf("expected only the historical cycle once C is excluded, got %+v", excluded)
+	}
+}
+
+func TestFindVersionedCyclesLimits(t *testing.T) {
+	goModGraph := `main A@v2.0.0
+main B@v1.0.0
+A@v1.0.0 B@v1.0.0
+A@v2.0.0 C@v1.0.0
+B@v1.0.0 A@v1.0.0
+C@v1.0.0 A@v2.0.0
+`
+	overview := generateGraph(goModGraph, []string{"main"})
+	cycles, stoppedBy := findVersionedCycles(parseVersionedGraph(goModGraph), overview.Versions, nil, cycleSearch{MaxCycles: 1})
+	if stoppedBy != cycleStopMaxCycles || len(cycles) != 1 {
+		t.Fatalf("expected one cycle truncated by max-cycles, got %q %+v", stoppedBy, cycles)
+	}
+
+	out := captureStdout(t, func() { printVersionedCycles(cycles, stoppedBy) })
+	if !strings.Contains(out, "(enumeration truncated by max-cycles after 1 cycles)") {
+		t.Fatalf("expected a truncation note, got:\n%s", out)
+	}
+}
@@ -17,7 +17,6 @@ limitations under the License.
 package cmd
 
 import (
-	"bytes"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -31,6 +30,7 @@ import (
 
 var dotOutput bool
 var svgOutput bool
+var mermaidOutput bool
 var testOnly bool
 var nonTestOnly bool
 var diffSplitTestOnly bool
@@ -134,7 +134,13 @@ Examples:
   depstat diff main --json
 
   # Output as DOT format for visualization
-  depstat diff main --dot | dot -Tsvg -o diff.svg`,
+  depstat diff main --dot | dot -Tsvg -o diff.svg
+
+  # Output as self-contained SVG (no Graphviz needed)
+  depstat diff main --svg > diff.svg
+
+  # Output as Mermaid for a PR comment
+  depstat diff main --mermaid`,
 	Args: cobra.RangeArgs(1, 2),
 	RunE: runDiff,
 }
@@ -146,8 +152,8 @@ func runDiff(cmd *cobra.Command, args []string) error {
 	if diffSplitTestOnly && (testOnly || nonTestOnly) {
 		return fmt.Errorf("--split-test-only cannot be combined with --test-only or --non-test-only")
 	}
-	if dotOutput && svgOutput {
-		return fmt.Errorf("--dot and --svg are mutually exclusive")
+	if (dotOutput && svgOutput) || (dotOutput && mermaidOutput) || (svgOutput && mermaidOutput) {
+		return fmt.Errorf("--dot, --svg and --mermaid are mutually exclusive")
 	}
 
 	baseRef := args[0]
@@ -158,81 +164,41 @@ func runDiff(cmd *cobra.Command, args []string) error {
 
 	needClassification := diffSplitTestOnly || testOnly || nonTestOnly
 
-	// Save current ref state to restore later.
-	originalRef, err := gitCurrentRefState()
-	if err != nil {
-		return fmt.Errorf("failed to get current git ref state: %w", err)
-	}
-	if dirty, err := gitWorkingTreeDirty(); err != nil {
-		return fmt.Errorf("failed to check working tree status: %w", err)
-	} else if dirty {
-		stashed, stashErr := gitStashPush()
-		if stashErr != nil {
-			return fmt.Errorf("working tree is dirty and automatic stash failed: %w", stashErr)
+	var (
+		baseDepGraph, headDepGraph *DependencyOverview
+		baseTestOnly, headTestOnly map[string]bool
+	)
+	labels := []string{"base", "head"}
+	shas, err := atGitRefs(labels, []string{baseRef, headRef}, func(i int) error {
+		depGraph := getDepInfo(mainModules)
+		// Classify test-only deps while the ref is still checked out.
+		var testOnlySet map[string]bool
+		if needClassification {
+			var err error
+			testOnlySet, err = classifyTestDeps(getAllDeps(depGraph.DirectDepList, depGraph.TransDepList))
+			if err != nil {
+				return fmt.Errorf("failed to classify %s dependencies as test-only/non-test: %w", labels[i], err)
+			}
 		}
-		if stashed {
-			defer func() {
-				if popErr := gitStashPop(); popErr != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to restore stashed changes: %v\n", popErr)
-				}
-			}()
+		if i == 0 {
+			baseDepGraph, baseTestOnly = depGraph, testOnlySet
+		} else {
+			headDepGraph, headTestOnly = depGraph, testOnlySet
 		}
-	}
-
-	// Resolve symbolic refs (like HEAD, HEAD~1) to SHAs before any
-	// checkout, since checkout changes what HEAD points to.
-	baseSHA, err := gitResolveRef(baseRef)
-	if err != nil {
-		return fmt.Errorf("failed to resolve base ref: %w", err)
-	}
-	headSHA, err := gitResolveRef(headRef)
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to resolve head ref: %w", err)
+		return err
 	}
+	baseSHA, headSHA := shas[0], shas[1]
 
-	// Ensure we restore the original state when done
-	defer func() {
-		if restoreErr := gitCheckout(originalRef); restoreErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to restore git ref %s: %v\n", originalRef, restoreErr)
-		}
-	}()
-
-	// Analyze base ref
-	if err := gitCheckout(baseSHA); err != nil {
-		return fmt.Errorf("failed to checkout base ref %s: %w", baseRef, err)
-	}
-	baseDepGraph := getDepInfo(mainModules)
 	baseStats := computeStats(baseDepGraph)
 	baseDeps := getAllDeps(baseDepGraph.DirectDepList, baseDepGraph.TransDepList)
 	baseEdges := getEdges(baseDepGraph.Graph)
-
-	// Classify test-only deps at base ref (while still checked out)
-	var baseTestOnly map[string]bool
-	if needClassification {
-		baseTestOnly, err = classifyTestDeps(baseDeps)
-		if err != nil {
-			return fmt.Errorf("failed to classify base dependencies as test-only/non-test: %w", err)
-		}
-	}
-
-	// Analyze head ref
-	if err := gitCheckout(headSHA); err != nil {
-		return fmt.Errorf("failed to checkout head ref %s: %w", headRef, err)
-	}
-	headDepGraph := getDepInfo(mainModules)
 	headStats := computeStats(headDepGraph)
 	headDeps := getAllDeps(headDepGraph.DirectDepList, headDepGraph.TransDepList)
 	headEdges := getEdges(headDepGraph.Graph)
 
-	// Classify test-only deps at head ref (while still checked out)
-	var headTestOnly map[string]bool
-	if needClassification {
-		headTestOnly, err = classifyTestDeps(headDeps)
-		if err != nil {
-			return fmt.Errorf("failed to classify head dependencies as test-only/non-test: %w", err)
-		}
-	}
-
 	// Compute diff
 	result := DiffResult{
 		BaseRef: baseRef,
@@ -309,6 +275,9 @@ func runDiff(cmd *cobra.Command, args []string) error {
 	if svgOutput {
 		return outputSVG(result, baseDepGraph, headDepGraph)
 	}
+	if mermaidOutput {
+		return outputDiffMermaid(result, baseDepGraph, headDepGraph)
+	}
 	return outputText(result)
 }
 
@@ -418,6 +387,58 @@ func diffSlices(a, b []string) []string {
 	return diff
 }
 
+// atGitRefs checks out each of refs in turn and calls visit with its index,
+// then restores the original ref. Uncommitted changes are stashed for the
+// duration. Refs are resolved to SHAs up front, since checkout changes what
+// HEAD points to, and the SHAs are returned. labels name the refs in errors.
+func atGitRefs(labels, refs []string, visit func(i int) error) (shas []string, err error) {
+	// Save current ref state to restore later.
+	originalRef, err := gitCurrentRefState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get current git ref state: %w", err)
+	}
+	if dirty, err := gitWorkingTreeDirty(); err != nil {
+		return nil, fmt.Errorf("failed to check working tree status: %w", err)
+	} else if dirty {
+		stashed, stashErr := gitStashPush()
+		if stashErr != nil {
+			return nil, fmt.Errorf("working tree is dirty and automatic stash failed: %w", stashErr)
+		}
+		if stashed {
+			defer func() {
+				if popErr := gitStashPop(); popErr != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to restore stashed changes: %v\n", popErr)
+				}
+			}()
+		}
+	}
+
+	for i, ref := range refs {
+		sha, err := gitResolveRef(ref)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve %s ref: %w", labels[i], err)
+		}
+		shas = append(shas, sha)
+	}
+
+	// Ensure we restore the original state when done
+	defer func() {
+		if restoreErr := gitCheckout(originalRef); restoreErr != nil {
+			fmt.Fprintf(os.Stderr, "warning: failed to restore git ref %s: %v\n", originalRef, restoreErr)
+		}
+	}()
+
+	for i, sha := range shas {
+		if err := gitCheckout(sha); err != nil {
+			return nil, fmt.Errorf("failed to checkout %s ref %s: %w", labels[i], refs[i], err)
+		}
+		if err := visit(i); err != nil {
+			return nil, err
+		}
+	}
+	return shas, nil
+}
+
 func gitResolveRef(ref string) (string, error) {
 	cmd := exec.Command("git", "rev-parse", ref)
 	if dir != "" {
@@ -699,13 +720,24 @@ func printSplitSection(title string, sec DiffFilteredSection) {
 	fmt.Println()
 }
 
-func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) error {
-	fmt.Println("strict digraph {")
-	fmt.Println("graph [overlap=false, rankdir=LR, label=\"Dependency Diff: " + result.BaseRef + ".." + result.HeadRef + "\", labelloc=t, fontsize=16];")
-	fmt.Println("node [shape=box, style=filled, fillcolor=white, fontsize=11];")
-	fmt.Println("edge [fontsize=9];")
-	fmt.Println()
+// diffGraph is the reduced change graph rendered by the diff DOT, Mermaid
+// and SVG outputs.
+type diffGraph struct {
+	// Nodes in sorted order
+	Nodes []string
+	// Status maps node to added, removed, changed, unchanged or main
+	Status map[string]string
+	// VersionChanges maps changed nodes to their version change
+	VersionChanges map[string]VersionChange
+	// MainModuleEdges connect main modules to their first changed dependency
+	MainModuleEdges []string
+	EdgesRemoved    []string
+	EdgesAdded      []string
+}
 
+// buildDiffGraph computes the nodes and transitively reduced edges of the
+// diff between baseGraph and headGraph.
+func buildDiffGraph(result DiffResult, baseGraph, headGraph *DependencyOverview) diffGraph {
 	// Build version change lookup
 	versionChangeMap := make(map[string]VersionChange)
 	for _, vc := range result.VersionChanges {
@@ -843,16 +875,35 @@ func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) erro
 	}
 	mainModuleEdges = dedupedMainEdges
 
-	// Output nodes with colors
-	fmt.Println("// Nodes")
 	var nodeNames []string
 	for n := range changedNodes {
 		nodeNames = append(nodeNames, n)
 	}
 	sort.Strings(nodeNames)
 
-	for _, node := range nodeNames {
-		status := changedNodes[node]
+	return diffGraph{
+		Nodes:           nodeNames,
+		Status:          changedNodes,
+		VersionChanges:  versionChangeMap,
+		MainModuleEdges: mainModuleEdges,
+		EdgesRemoved:    edgesRemoved,
+		EdgesAdded:      edgesAdded,
+	}
+}
+
+func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) error {
+	dg := buildDiffGraph(result, baseGraph, headGraph)
+
+	fmt.Println("strict digraph {")
+	fmt.Println("graph [overlap=false, rankdir=LR, label=\"Dependency Diff: " + result.BaseRef + ".." + result.HeadRef + "\", labelloc=t, fontsize=16];")
+	fmt.Println("node [shape=box, style=filled, fillcolor=white, fontsize=11];")
+	fmt.Println("edge [fontsize=9];")
+	fmt.Println()
+
+	// Output nodes with colors
+	fmt.Println("// Nodes")
+	for _, node := range dg.Nodes {
+		status := dg.Status[node]
 		color := "white"
 		style := "filled"
 		label := node
@@ -864,7 +915,7 @@ func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) erro
 			style = "filled,dashed"
 		case "changed":
 			color = "#ffffcc" // yellow
-			if vc, ok := versionChangeMap[node]; ok {
+			if vc, ok := dg.VersionChanges[node]; ok {
 				label = fmt.Sprintf("%s\\n%s → %s", node, vc.Before, vc.After)
 			}
 		case "main":
@@ -875,9 +926,9 @@ func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) erro
 	fmt.Println()
 
 	// Output main module edges (thin, gray)
-	if len(mainModuleEdges) > 0 {
+	if len(dg.MainModuleEdges) > 0 {
 		fmt.Println("// Main module edges")
-		for _, edge := range mainModuleEdges {
+		for _, edge := range dg.MainModuleEdges {
 			parts := strings.Split(edge, " -> ")
 			if len(parts) == 2 {
 				fmt.Printf("\"%s\" -> \"%s\" [color=\"gray\", style=\"dotted\"];\n", parts[0], parts[1])
@@ -887,9 +938,9 @@ func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) erro
 	}
 
 	// Output reduced edges
-	if len(edgesRemoved) > 0 {
+	if len(dg.EdgesRemoved) > 0 {
 		fmt.Println("// Removed edges")
-		for _, edge := range edgesRemoved {
+		for _, edge := range dg.EdgesRemoved {
 			parts := strings.Split(edge, " -> ")
 			if len(parts) == 2 {
 				fmt.Printf("\"%s\" -> \"%s\" [color=\"red\", style=\"dashed\"];\n", parts[0], parts[1])
@@ -898,9 +949,9 @@ func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) erro
 		fmt.Println()
 	}
 
-	if len(edgesAdded) > 0 {
+	if len(dg.EdgesAdded) > 0 {
 		fmt.Println("// Added edges")
-		for _, edge := range edgesAdded {
+		for _, edge := range dg.EdgesAdded {
 			parts := strings.Split(edge, " -> ")
 			if len(parts) == 2 {
 				fmt.Printf("\"%s\" -> \"%s\" [color=\"green\", style=\"bold\"];\n", parts[0], parts[1])
@@ -912,51 +963,116 @@ func outputDOT(result DiffResult, baseGraph, headGraph *DependencyOverview) erro
 	return nil
 }
 
-func outputSVG(result DiffResult, baseGraph, headGraph *DependencyOverview) error {
-	dot, err := captureDOTOutput(func() error {
-		return outputDOT(result, baseGraph, headGraph)
-	})
-	if err != nil {
-		return err
-	}
+func outputDiffMermaid(result DiffResult, baseGraph, headGraph *DependencyOverview) error {
+	dg := buildDiffGraph(result, baseGraph, headGraph)
 
-	cmd := exec.Command("dot", "-Tsvg")
-	cmd.Stdin = strings.NewReader(dot)
-	cmd.Stdout = os.Stdout
-	var stderr bytes.Buffer
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to render DOT as SVG via graphviz 'dot': %w: %s", err, strings.TrimSpace(stderr.String()))
+	g := newMermaidGraph("Dependency Diff: "+result.BaseRef+".."+result.HeadRef, "LR")
+	for _, node := range dg.Nodes {
+		status := dg.Status[node]
+		label := node
+		class := status
+		switch status {
+		case "changed":
+			if vc, ok := dg.VersionChanges[node]; ok {
+				label = fmt.Sprintf("%s\n%s → %s", node, vc.Before, vc.After)
+			}
+		case "main":
+			class = "context"
+		}
+		g.AddNode(node, label, class)
+	}
+	addEdges := func(edges []string, style, color string) {
+		for _, edge := range edges {
+			parts := strings.Split(edge, " -> ")
+			if len(parts) == 2 {
+				g.AddEdge(mermaidEdge{From: parts[0], To: parts[1], Style: style, Color: color})
+			}
+		}
 	}
+	addEdges(dg.MainModuleEdges, mermaidDotted, "gray")
+	addEdges(dg.EdgesRemoved, mermaidDotted, "red")
+	addEdges(dg.EdgesAdded, mermaidThick, "green")
+
+	fmt.Print(g.String())
 	return nil
 }
 
-func captureDOTOutput(fn func() error) (string, error) {
-	oldStdout := os.Stdout
-	r, w, err := os.Pipe()
-	if err != nil {
-		return "", err
-	}
-	os.Stdout = w
-
-	runErr := fn()
-	closeErr := w.Close()
-	os.Stdout = oldStdout
+// diffSVGLegend matches the node colours used by outputSVG.
+var diffSVGLegend = []svgLegendEntry{
+	{"#E8F5E9", "#388E3C", "Added"},
+	{"#FFEBEE", "#D32F2F", "Removed"},
+	{"#FFFDE7", "#F9A825", "Version change"},
+	{"#F5F5F5", "#9E9E9E", "Main module"},
+}
 
-	var buf bytes.Buffer
-	_, readErr := io.Copy(&buf, r)
-	_ = r.Close()
+// outputSVG renders the diff change graph as a self-contained SVG, using the
+// same nodes and reduced edges as outputDOT.
+func outputSVG(result DiffResult, baseGraph, headGraph *DependencyOverview) error {
+	dg := buildDiffGraph(result, baseGraph, headGraph)
 
-	if runErr != nil {
-		return "", runErr
-	}
-	if closeErr != nil {
-		return "", closeErr
+	var edges []svgEdge
+	edgeKind := map[svgEdge]string{}
+	addEdges := func(list []string, kind string) {
+		for _, edge := range list {
+			parts := strings.Split(edge, " -> ")
+			if len(parts) != 2 {
+				continue
+			}
+			e := svgEdge{From: parts[0], To: parts[1]}
+			if _, ok := edgeKind[e]; !ok {
+				edges = append(edges, e)
+			}
+			edgeKind[e] = kind
+		}
 	}
-	if readErr != nil {
-		return "", readErr
+	addEdges(dg.MainModuleEdges, "main")
+	addEdges(dg.EdgesRemoved, "removed")
+	addEdges(dg.EdgesAdded, "added")
+
+	var mainModules []string
+	for _, node := range dg.Nodes {
+		if dg.Status[node] == "main" {
+			mainModules = append(mainModules, node)
+		}
 	}
-	return buf.String(), nil
+
+	fmt.Print(renderSVGDiagram(svgDiagram{
+		Title:    fmt.Sprintf("Dependency Diff: %s..%s", result.BaseRef, result.HeadRef),
+		Subtitle: fmt.Sprintf("+%d added, -%d removed, ~%d version changes", len(result.Added), len(result.Removed), len(result.VersionChanges)),
+		Nodes:    dg.Nodes,
+		Edges:    edges,
+		Layout:   layoutOptions{Roots: mainModules},
+		Legend:   diffSVGLegend,
+		NodeStyle: func(node string) svgNodeStyle {
+			s := svgNodeStyle{Color: nodeColor{"#FFFFFF", "#9E9E9E", "#333333"}}
+			switch dg.Status[node] {
+			case "added":
+				s.Color = nodeColor{"#E8F5E9", "#388E3C", "#1B5E20"}
+			case "removed":
+				s.Color = nodeColor{"#FFEBEE", "#D32F2F", "#B71C1C"}
+				s.Dashed = true
+			case "changed":
+				s.Color = nodeColor{"#FFFDE7", "#F9A825", "#5D4037"}
+				if vc, ok := dg.VersionChanges[node]; ok {
+					s.Label = fmt.Sprintf("%s %s → %s", node, vc.Before, vc.After)
+				}
+			case "main":
+				s.Color = nodeColor{"#F5F5F5", "#9E9E9E", "#424242"}
+			}
+			return s
+		},
+		EdgeStyle: func(e svgEdge, _ int) svgEdgeStyle {
+			switch edgeKind[e] {
+			case "added":
+				return svgEdgeStyle{Stroke: "#388E3C", Width: "2"}
+			case "removed":
+				return svgEdgeStyle{Stroke: "#D32F2F", Dashed: true}
+			default:
+				return svgEdgeStyle{Stroke: "#9E9E9E", Width: "1", Dashed: true}
+			}
+		},
+	}))
+	return nil
 }
 
 // transitiveReduceEdges removes diff edges that are implied by longer paths
@@ -964,9 +1080,16 @@ func captureDOTOutput(fn func() error) (string, error) {
 // contains at least one other diff edge. This prevents genuinely new edges
 // (like A newly depending on B) from being pruned via pre-existing paths.
 func transitiveReduceEdges(diffEdges []string, fullGraph map[string][]string, diffNodes map[string]bool) []string {
-	diffEdgeSet := make(map[string]bool)
+	var edges [][2]string
+	diffEdgeSet := make(map[[2]string]bool)
 	for _, e := range diffEdges {
-		diffEdgeSet[e] = true
+		parts := strings.Split(e, " -> ")
+		if len(parts) != 2 {
+			continue
+		}
+		edge := [2]string{parts[0], parts[1]}
+		edges = append(edges, edge)
+		diffEdgeSet[edge] = true
 	}
 
 	// Project the full graph onto just the diff-relevant nodes
@@ -980,71 +1103,12 @@ func transitiveReduceEdges(diffEdges []string, fullGraph map[string][]string, di
 	}
 
 	var reduced []string
-	for _, edge := range diffEdges {
-		parts := strings.Split(edge, " -> ")
-		if len(parts) != 2 {
-			continue
-		}
-		if !reachableViaDiffPath(parts[0], parts[1], subGraph, diffEdgeSet) {
-			reduced = append(reduced, edge)
-		}
+	for _, e := range reduceEdges(edges, subGraph, diffEdgeSet) {
+		reduced = append(reduced, e[0]+" -> "+e[1])
 	}
 	return reduced
 }
 
-// reachableViaDiffPath checks if dst is reachable from src via a path of
-// length > 1 (excluding the direct src→dst edge) where at least one
-// intermediate edge is itself a diff edge.
-func reachableViaDiffPath(src, dst string, graph map[string][]string, diffEdgeSet map[string]bool) bool {
-	type state struct {
-		node    string
-		hasDiff bool
-	}
-	reachedWithDiff := make(map[string]bool)
-	reachedNoDiff := make(map[string]bool)
-	queue := []state{}
-
-	for _, n := range graph[src] {
-		if n == dst {
-			continue
-		}
-		hasDiff := diffEdgeSet[src+" -> "+n]
-		if hasDiff {
-			if !reachedWithDiff[n] {
-				reachedWithDiff[n] = true
-				queue = append(queue, state{n, true})
-			}
-		} else if !reachedNoDiff[n] {
-			reachedNoDiff[n] = true
-			queue = append(queue, state{n, false})
-		}
-	}
-
-	for len(queue) > 0 {
-		cur := queue[0]
-		queue = queue[1:]
-
-		if cur.node == dst && cur.hasDiff {
-			return true
-		}
-
-		for _, n := range graph[cur.node] {
-			hasDiff := cur.hasDiff || diffEdgeSet[cur.node+" -> "+n]
-			if hasDiff {
-				if !reachedWithDiff[n] {
-					reachedWithDiff[n] = true
-					queue = append(queue, state{n, true})
-				}
-			} else if !reachedNoDiff[n] {
-				reachedNoDiff[n] = true
-				queue = append(queue, state{n, false})
-			}
-		}
-	}
-
-	return false
-}
-
 // computeVersionChanges returns modules present in both base and head
 // whose effective versions differ.
 func computeVersionChanges(base, head *DependencyOverview) []VersionChange {
@@ -1207,7 +1271,8 @@ func init() {
 	diffCmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory containing the module to evaluate")
 	diffCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output in JSON format")
 	diffCmd.Flags().BoolVarP(&dotOutput, "dot", "", false, "Output in DOT format for Graphviz")
-	diffCmd.Flags().BoolVarP(&svgOutput, "svg", "s", false, "Render DOT output as SVG (requires graphviz 'dot')")
+	diffCmd.Flags().BoolVarP(&svgOutput, "svg", "s", false, "Output the change graph as a self-contained SVG diagram")
+	diffCmd.Flags().BoolVar(&mermaidOutput, "mermaid", false, "Output the change graph as a Mermaid flowchart")
 	diffCmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Include edge-level changes")
 	diffCmd.Flags().StringSliceVarP(&mainModules, "mainModules", "m", []string{}, "Specify main modules")
 	diffCmd.Flags().BoolVar(&testOnly, "test-only", false, "Only show test-only dependency changes (uses go mod why -m)")
@@ -0,0 +1,299 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	explainBaseRef string
+	explainHeadRef string
+)
+
+// VersionRequirement is one requirement on the explained module in the
+// "go mod graph" output.
+type VersionRequirement struct {
+	Requirer        string `json:"requirer"`
+	RequirerVersion string `json:"requirerVersion,omitempty"`
+	Version         string `json:"version"`
+	// RequirerSelected is set when RequirerVersion is the version of the
+	// requirer in the build, and cleared for superseded versions.
+	RequirerSelected bool `json:"requirerSelected"`
+	// Determining marks the requirement that set the effective version.
+	Determining bool `json:"determining,omitempty"`
+}
+
+// VersionExplanation explains the effective version of a module.
+type VersionExplanation struct {
+	Module       string               `json:"module"`
+	Found        bool                 `json:"found"`
+	Version      string               `json:"version"`
+	MainModules  []string             `json:"mainModules"`
+	Requirements []VersionRequirement `json:"requirements"`
+	// Path is the shortest path from a main module to the requirer of the
+	// determining requirement.
+	Path []string `json:"path,omitempty"`
+}
+
+// VersionChangeExplanation explains the version of a module at two refs.
+type VersionChangeExplanation struct {
+	Module  string             `json:"module"`
+	BaseRef string             `json:"baseRef"`
+	HeadRef string             `json:"headRef"`
+	Changed bool               `json:"changed"`
+	Base    VersionExplanation `json:"base"`
+	Head    VersionExplanation `json:"head"`
+}
+
+var explainVersionCmd = &cobra.Command{
+	Use:   "explain-version <module>",
+	Short: "Explain which requirement selected a module's version",
+	Long: `List every module that requires the given module in "go mod graph",
+with the version each one asks for, and mark the requirement that determined
+the version in the build. Minimal version selection picks the highest
+requested version, so the determining requirer is the one asking for that
+version; the shortest dependency path to it is shown as with "depstat why".
+
+With --base, the module is explained at two git refs to show why its version
+changed.
+
+Examples:
+  # Which requirer forces the version of golang.org/x/net?
+  depstat explain-version golang.org/x/net
+
+  # Why did the version change on this branch?
+  depstat explain-version golang.org/x/net --base main --json`,
+	Args: cobra.ExactArgs(1),
+	RunE: runExplainVersion,
+}
+
+func runExplainVersion(cmd *cobra.Command, args []string) error {
+	target := args[0]
+	if explainBaseRef == "" {
+		if cmd.Flags().Changed("head") {
+			return fmt.Errorf("--head requires --base")
+		}
+		raw := getGoModGraph()
+		explanation := explainVersion(raw, getDepInfoFromGraph(raw, mainModules), target)
+		if jsonOutput {
+			return outputExplainVersionJSON(explanation)
+		}
+		printVersionExplanation(explanation)
+		return nil
+	}
+
+	var explanations [2]VersionExplanation
+	_, err := atGitRefs([]string{"base", "head"}, []string{explainBaseRef, explainHeadRef}, func(i int) error {
+		raw := getGoModGraph()
+		explanations[i] = explainVersion(raw, getDepInfoFromGraph(raw, mainModules), target)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	change := VersionChangeExplanation{
+		Module:  target,
+		BaseRef: explainBaseRef,
+		HeadRef: explainHeadRef,
+		Changed: explanations[0].Version != explanations[1].Version,
+		Base:    explanations[0],
+		Head:    explanations[1],
+	}
+	if jsonOutput {
+		return outputExplainVersionJSON(change)
+	}
+	printVersionChangeExplanation(change)
+	return nil
+}
+
+// explainVersion lists the requirements on target in the "go mod graph"
+// output and marks the one that determined its version in depGraph. Among
+// the requirements asking for that version, requirers in the build beat
+// superseded versions, other modules beat the main modules (whose go.mod
+// only records the result from Go 1.17 on), and then the closest requirer
+// wins.
+func explainVersion(goModGraphOutput string, depGraph *DependencyOverview, target string) VersionExplanation {
+	explanation := VersionExplanation{
+		Module:       target,
+		Version:      depGraph.Versions[target],
+		MainModules:  depGraph.MainModules,
+		Requirements: []VersionRequirement{},
+	}
+	_, explanation.Found = depGraph.Versions[target]
+	if contains(depGraph.MainModules, target) {
+		explanation.Found = false
+	}
+	if !explanation.Found {
+		return explanation
+	}
+
+	for lhs, rhss := range parseVersionedGraph(goModGraphOutput) {
+		for _, rhs := range rhss {
+			if rhs.name != target || lhs.name == target {
+				continue
+			}
+			explanation.Requirements = append(explanation.Requirements, VersionRequirement{
+				Requirer:         lhs.name,
+				RequirerVersion:  lhs.version,
+				Version:          rhs.version,
+				RequirerSelected: contains(depGraph.MainModules, lhs.name) || depGraph.Versions[lhs.name] == lhs.version,
+			})
+		}
+	}
+	sort.Slice(explanation.Requirements, func(i, j int) bool {
+		a, b := explanation.Requirements[i], explanation.Requirements[j]
+		if a.Version != b.Version {
+			return versionGreater(a.Version, b.Version)
+		}
+		if a.Requirer != b.Requirer {
+			return a.Requirer < b.Requirer
+		}
+		return versionGreater(a.RequirerVersion, b.RequirerVersion)
+	})
+
+	best := -1
+	var bestPath []string
+	rank := func(r VersionRequirement, path []string) []int {
+		rank := []int{1, 1, len(path)}
+		if r.RequirerSelected {
+			rank[0] = 0
+		}
+		if !contains(depGraph.MainModules, r.Requirer) {
+			rank[1] = 0
+		}
+		if path == nil {
+			// not reachable from the main modules any more
+			rank[2] = len(depGraph.Versions) + 1
+		}
+		return rank
+	}
+	for i, r := range explanation.Requirements {
+		if r.Version != explanation.Version {
+			continue
+		}
+		var path []string
+		if paths := kShortestPaths(depGraph.Graph, depGraph.MainModules, r.Requirer, 1); len(paths) > 0 {
+			path = paths[0]
+		}
+		if best < 0 || rankLess(rank(r, path), rank(explanation.Requirements[best], bestPath)) {
+			best, bestPath = i, path
+		}
+	}
+	if best >= 0 {
+		explanation.Requirements[best].Determining = true
+		explanation.Path = bestPath
+	}
+	return explanation
+}
+
+// rankLess compares two ranks lexicographically.
+func rankLess(a, b []int) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return false
+}
+
+func outputExplainVersionJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func printVersionExplanation(e VersionExplanation) {
+	if !e.Found {
+		fmt.Printf("Module %q not found in the dependency graph.\n", e.Module)
+		return
+	}
+	fmt.Printf("Why is %s at %s?\n", e.Module, e.Version)
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println()
+
+	fmt.Printf("Required by (%d):\n", len(e.Requirements))
+	for _, r := range e.Requirements {
+		marker := "  "
+		if r.Determining {
+			marker = "* "
+		}
+		requirer := r.Requirer
+		if r.RequirerVersion != "" {
+			requirer += "@" + r.RequirerVersion
+		}
+		note := ""
+		if !r.RequirerSelected {
+			note = " (superseded)"
+		}
+		fmt.Printf("  %s%s requires %s%s\n", marker, requirer, r.Version, note)
+	}
+	fmt.Println()
+
+	for _, r := range e.Requirements {
+		if !r.Determining {
+			continue
+		}
+		requirer := r.Requirer
+		if r.RequirerVersion != "" {
+			requirer += "@" + r.RequirerVersion
+		}
+		fmt.Printf("%s is determined by %s.\n", e.Version, requirer)
+		if len(e.Path) > 0 {
+			fmt.Printf("Path: %s\n", strings.Join(e.Path, " -> "))
+		}
+		return
+	}
+	fmt.Printf("No requirement asks for %s; it may come from a replace directive.\n", e.Version)
+}
+
+func printVersionChangeExplanation(c VersionChangeExplanation) {
+	base, head := c.Base.Version, c.Head.Version
+	if !c.Base.Found {
+		base = "absent"
+	}
+	if !c.Head.Found {
+		head = "absent"
+	}
+	if c.Changed {
+		fmt.Printf("%s: %s (%s) -> %s (%s)\n\n", c.Module, base, c.BaseRef, head, c.HeadRef)
+	} else {
+		fmt.Printf("%s: unchanged at %s between %s and %s\n\n", c.Module, head, c.BaseRef, c.HeadRef)
+	}
+	fmt.Printf("At %s:\n", c.BaseRef)
+	printVersionExplanation(c.Base)
+	fmt.Println()
+	fmt.Printf("At %s:\n", c.HeadRef)
+	printVersionExplanation(c.Head)
+}
+
+func init() {
+	rootCmd.AddCommand(explainVersionCmd)
+	explainVersionCmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory containing the module to evaluate")
+	explainVersionCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output in JSON format")
+	explainVersionCmd.Flags().StringVar(&explainBaseRef, "base", "", "Also explain the version at this git ref and compare")
+	explainVersionCmd.Flags().StringVar(&explainHeadRef, "head", "HEAD", "With --base, the git ref to compare against the base")
+	explainVersionCmd.Flags().StringSliceVarP(&mainModules, "mainModules", "m", []string{}, "Specify main modules")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import "testing"
+
+const explainVersionGraph = `example.com/main example.com/a@v1.0.0
+example.com/main example.com/b@v1.0.0
+example.com/main example.com/x@v1.3.0
+example.com/a@v1.0.0 example.com/x@v1.1.0
+example.com/b@v1.0.0 example.com/c@v1.0.0
+example.com/b@v0.9.0 example.com/x@v1.3.0
+example.com/c@v1.0.0 example.com/x@v1.3.0
+`
+
+func TestExplainVersion(t *testing.T) {
+	depGraph := generateGraph(explainVersionGraph, []string{"example.com/main"})
+	e := explainVersion(explainVersionGraph, &depGraph, "example.com/x")
+
+	if !e.Found || e.Version != "v1.3.0" {
+		t.Fatalf("expected x at v1.3.0, got %+v", e)
+	}
+	if len(e.Requirements) != 4 {
+		t.Fatalf("expected 4 requirements, got %+v", e.Requirements)
+	}
+	// Highest requested version first, the lower one from a last.
+	if last := e.Requirements[3]; last.Requirer != "example.com/a" || last.Version != "v1.1.0" {
+		t.Fatalf("unexpected last requirement: %+v", last)
+	}
+
+	var determining []VersionRequirement
+	for _, r := range e.Requirements {
+		if r.Determining {
+			determining = append(determining, r)
+		}
+		if r.Requirer == "example.com/b" && r.RequirerSelected {
+			t.Fatalf("b@v0.9.0 is superseded by b@v1.0.0: %+v", r)
+		}
+	}
+	// The main module only records the version and b@v0.9.0 is superseded,
+	// so c decides.
+	if len(determining) != 1 || determining[0].Requirer != "example.com/c" || determining[0].RequirerVersion != "v1.0.0" {
+		t.Fatalf("expected c@v1.0.0 to determine the version, got %+v", determining)
+	}
+	if want := []string{"example.com/main", "example.com/b", "example.com/c"}; !isSliceSame(e.Path, want) {
+		t.Fatalf("path = %v, want %v", e.Path, want)
+	}
+}
+
+func TestExplainVersionNotFound(t *testing.T) {
+	depGraph := generateGraph(explainVersionGraph, []string{"example.com/main"})
+	if e := explainVersion(explainVersionGraph, &depGraph, "example.com/missing"); e.Found {
+		t.Fatalf("expected missing module not to be found, got %+v", e)
+	}
+}
@@ -31,9 +31,22 @@ var dep string
 var showEdgeTypes bool
 var graphDotOutput bool
 var graphJSONOutput bool
+var graphMermaidOutput bool
+var graphFormat string
+var graphSVGOutput bool
+var graphHTMLPath string
 var graphOutputPath string
 var graphTopMode string
 var graphTopN int
+var graphDescendants bool
+var graphMaxDepth int
+var graphMinDepth int
+var graphReverse bool
+var graphClusterBy string
+var graphClusterPatterns []string
+var graphCollapse []string
+var graphReduce bool
+var graphCondense bool
 
 type graphNode struct {
 	Module       string `json:"module"`
@@ -41,6 +54,13 @@ type graphNode struct {
 	OutDegree    int    `json:"outDegree"`
 	Depth        int    `json:"depth"` // -1 means unreachable from any main module
 	IsMainModule bool   `json:"isMainModule"`
+	// Betweenness is the normalised share of shortest paths between other
+	// modules that pass through this one.
+	Betweenness float64 `json:"betweenness"`
+	PageRank    float64 `json:"pageRank"`
+	// Dependents is the number of modules depending on this one, directly
+	// or transitively.
+	Dependents int `json:"dependents"`
 }
 
 type graphEdge struct {
@@ -49,10 +69,13 @@ type graphEdge struct {
 }
 
 type graphRankings struct {
-	Mode string      `json:"mode"`
-	N    int         `json:"n"`
-	In   []graphNode `json:"in,omitempty"`
-	Out  []graphNode `json:"out,omitempty"`
+	Mode        string      `json:"mode"`
+	N           int         `json:"n"`
+	In          []graphNode `json:"in,omitempty"`
+	Out         []graphNode `json:"out,omitempty"`
+	Betweenness []graphNode `json:"betweenness,omitempty"`
+	PageRank    []graphNode `json:"pageRank,omitempty"`
+	Dependents  []graphNode `json:"dependents,omitempty"`
 }
 
 var graphCmd = &cobra.Command{
@@ -64,16 +87,108 @@ var graphCmd = &cobra.Command{
 
 	Use --show-edge-types to distinguish between direct and transitive dependencies:
 	- Direct edges (solid blue): from main module(s) to their direct dependencies
-	- Transitive edges (dashed gray): dependencies of dependencies`,
+	- Transitive edges (dashed gray): dependencies of dependencies
+
+	Use --dep to graph the modules around one dependency: every path from the
+	main modules to it, plus everything it depends on (disable with
+	--descendants=false). Add --reverse to graph everything that depends on it
+	instead, up to the main modules. The DOT output lists every chain through
+	the dependency; when there are more than 10000 chains, or with --reverse,
+	it lists every edge once instead.
+
+	Use --max-depth N and --min-depth N to keep only modules whose shortest
+	depth from the main modules is within the given range. With --reverse,
+	--max-depth counts edges up from the dependency instead.
+
+	Use --cluster-by domain|org to draw modules sharing a host (github.com) or
+	an organisation (github.com/aws) inside a DOT cluster, or --cluster-by
+	pattern with --cluster-pattern globs for custom groups. Use --collapse
+	<glob> to merge all matching modules into a single node with their edges
+	aggregated, e.g. --collapse 'github.com/aws/aws-sdk-go-v2/*'.
+
+	Use --annotate to label DOT nodes with their effective version, draw main
+	modules with a double border and colour test-only modules (classified via go
+	mod why -m). --attributes file.json overlays results from other tools, e.g.
+	{"github.com/foo/bar": {"color": "#ffcdd2", "label": "CVE-2024-1234"}}.
+
+	Use --reduce to drop every edge implied by a longer path (the transitive
+	reduction) and --condense to merge each cycle into a single node so the
+	graph becomes a DAG. A cycle through main modules becomes a main node that
+	lists them. Both apply to every output format.
+
+	Use --top with in, out or both to rank modules by degree, or with
+	betweenness, pagerank or dependents to find bridge modules that sit on many
+	dependency paths. All metrics are included in the --json node objects.
+
+	Use --mermaid to print a Mermaid flowchart instead, which GitHub renders natively,
+	or --svg to print a self-contained SVG diagram without needing Graphviz.
+
+	Use --format graphml or --format gexf to export the graph with node attributes
+	(version, depth, in/out degree, main module, test-only) and typed edges for
+	tools such as Gephi or yEd. Test-only status is classified via go mod why -m.
+
+	Use --html out.html to write a self-contained interactive explorer that works
+	offline: search modules, expand dependencies and dependents, highlight why
+	paths and hide test-only modules.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		if graphDotOutput && graphJSONOutput {
 			return fmt.Errorf("--dot and --json are mutually exclusive")
 		}
+		if graphMermaidOutput && (graphDotOutput || graphJSONOutput) {
+			return fmt.Errorf("--mermaid cannot be combined with --dot or --json")
+		}
+		if graphSVGOutput && (graphDotOutput || graphJSONOutput || graphMermaidOutput) {
+			return fmt.Errorf("--svg cannot be combined with --dot, --json or --mermaid")
+		}
+		if graphFormat != "" && graphFormat != graphFormatGraphML && graphFormat != graphFormatGEXF {
+			return fmt.Errorf("--format must be one of: %s, %s", graphFormatGraphML, graphFormatGEXF)
+		}
+		if graphFormat != "" && (graphDotOutput || graphJSONOutput || graphMermaidOutput || graphSVGOutput || graphTopMode != "" || dep != "") {
+			return fmt.Errorf("--format cannot be combined with --dot, --json, --mermaid, --svg, --top or --dep")
+		}
+		if graphHTMLPath != "" && (graphDotOutput || graphJSONOutput || graphMermaidOutput || graphSVGOutput || graphFormat != "" || graphTopMode != "" || dep != "") {
+			return fmt.Errorf("--html cannot be combined with --dot, --json, --mermaid, --svg, --format, --top or --dep")
+		}
+		if dep == "" && (cmd.Flags().Changed("descendants") || graphReverse) {
+			return fmt.Errorf("--descendants and --reverse require --dep")
+		}
+		if graphReverse && cmd.Flags().Changed("descendants") {
+			return fmt.Errorf("--reverse cannot be combined with --descendants")
+		}
+		if graphMinDepth < 0 {
+			return fmt.Errorf("--min-depth must be >= 0")
+		}
+		if graphMinDepth > 0 && dep != "" {
+			return fmt.Errorf("--min-depth cannot be combined with --dep")
+		}
+		if graphMaxDepth >= 0 && graphMinDepth > graphMaxDepth {
+			return fmt.Errorf("--min-depth must not exceed --max-depth")
+		}
+		if graphClusterBy != "" && graphClusterBy != clusterByDomain && graphClusterBy != clusterByOrg && graphClusterBy != clusterByPattern {
+			return fmt.Errorf("--cluster-by must be one of: %s, %s, %s", clusterByDomain, clusterByOrg, clusterByPattern)
+		}
+		if (graphClusterBy == clusterByPattern) != (len(graphClusterPatterns) > 0) {
+			return fmt.Errorf("--cluster-by pattern and --cluster-pattern must be used together")
+		}
+		if attributesPath != "" && !annotateOutput {
+			return fmt.Errorf("--attributes requires --annotate")
+		}
+		if annotateOutput && (graphJSONOutput || graphMermaidOutput || graphSVGOutput || graphFormat != "" || graphHTMLPath != "" || graphTopMode != "") {
+			return fmt.Errorf("--annotate is only supported for DOT output")
+		}
+		if graphClusterBy != "" && (graphMermaidOutput || graphSVGOutput || graphFormat != "" || graphHTMLPath != "") {
+			return fmt.Errorf("--cluster-by is only supported for DOT and JSON output")
+		}
 		if graphTopMode != "" && graphDotOutput {
 			return fmt.Errorf("cannot use --top with --dot")
 		}
-		if graphTopMode != "" && graphTopMode != "in" && graphTopMode != "out" && graphTopMode != "both" {
-			return fmt.Errorf("--top must be one of: in, out, both")
+		if graphTopMode != "" && (graphMermaidOutput || graphSVGOutput) {
+			return fmt.Errorf("cannot use --top with --mermaid or --svg")
+		}
+		switch graphTopMode {
+		case "", metricIn, metricOut, "both", metricBetweenness, metricPageRank, metricDependents:
+		default:
+			return fmt.Errorf("--top must be one of: in, out, both, betweenness, pagerank, dependents")
 		}
 		if graphTopMode != "" && graphTopN <= 0 {
 			return fmt.Errorf("-n must be > 0")
@@ -85,25 +200,141 @@ var graphCmd = &cobra.Command{
 		if len(overview.MainModules) == 0 {
 			return fmt.Errorf("no main modules remain after exclusions; adjust --exclude-modules or --mainModules")
 		}
+		var collapsed, components []collapsedGroup
+		if len(graphCollapse) > 0 {
+			overview, collapsed = collapseModules(overview, graphCollapse)
+		}
+		if graphCondense {
+			overview, components = condenseGraph(overview)
+		}
+		if graphReduce {
+			overview = transitiveReduction(overview)
+		}
 		nodes, edgeObjects := buildGraphTopology(overview)
+		if dep == "" && (graphMinDepth > 0 || graphMaxDepth >= 0) {
+			overview, nodes, edgeObjects = limitGraphDepth(overview, nodes, edgeObjects, graphMinDepth, graphMaxDepth)
+		}
 
 		if graphTopMode != "" && !graphJSONOutput && !graphDotOutput {
 			printTopNodes(nodes, graphTopMode, graphTopN)
 			return nil
 		}
+		if graphFormat != "" || graphHTMLPath != "" {
+			allDeps := getAllDeps(overview.DirectDepList, overview.TransDepList)
+			sort.Strings(allDeps)
+			testOnlySet, err := classifyTestDeps(allDeps)
+			if err != nil {
+				return fmt.Errorf("failed to classify dependencies: %w", err)
+			}
+			exportNodes, exportEdges := buildExportGraph(overview, nodes, edgeObjects, testOnlySet)
+			switch {
+			case graphHTMLPath != "":
+				page, err := renderGraphHTML(overview.MainModules, exportNodes, exportEdges)
+				if err != nil {
+					return err
+				}
+				if err := os.WriteFile(graphHTMLPath, []byte(page), 0644); err != nil {
+					return err
+				}
+				fmt.Printf("\nCreated %s file!\n", graphHTMLPath)
+			case graphFormat == graphFormatGraphML:
+				fmt.Print(renderGraphML(exportNodes, exportEdges))
+			default:
+				fmt.Print(renderGEXF(exportNodes, exportEdges))
+			}
+			return nil
+		}
+		var focusEdges []graphEdge
+		if dep != "" {
+			var err error
+			if graphReverse {
+				focusEdges, err = reverseSubgraph(overview, dep, graphMaxDepth)
+			} else {
+				focusEdges, err = focusSubgraph(overview, dep, focusOptions{Descendants: graphDescendants, MaxDepth: graphMaxDepth})
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if graphSVGOutput {
+			if dep != "" {
+				fmt.Print(getSVGForSingleDep(overview, focusEdges, dep))
+			} else {
+				fmt.Print(getSVGForAllDeps(overview, nodes, edgeObjects, showEdgeTypes))
+			}
+			return nil
+		}
+		if graphMermaidOutput {
+			if dep != "" {
+				fmt.Print(getMermaidForSingleDep(focusEdges, dep))
+			} else {
+				fmt.Print(getMermaidForAllDepsWithTypes(overview, showEdgeTypes))
+			}
+			return nil
+		}
 		// strict ensures that there is only one edge between two vertices
 		// overlap = false ensures the vertices don't overlap
 		fileContents := "strict digraph {\ngraph [overlap=false];\n"
 
 		// graph to be generated is based around input dep
 		if dep != "" {
+			// draw the chains through dep as before, unless there are too
+			// many of them, in which case every edge is drawn once
 			var chains []Chain
-			var temp Chain
-			getAllChains(overview.MainModules[0], overview.Graph, temp, &chains)
-			fileContents += getFileContentsForSingleDep(chains, dep)
+			ok := false
+			if !graphReverse {
+				chains, ok = focusChains(overview.MainModules, focusEdges, focusChainLimit)
+			}
+			if ok {
+				fileContents += getFileContentsForSingleDep(chains, dep)
+			} else {
+				fileContents += getFileContentsForFocus(focusEdges, dep)
+			}
 		} else {
 			fileContents += getFileContentsForAllDepsWithTypes(overview, showEdgeTypes)
 		}
+		// the modules drawn in the DOT output, with mainNode written as MainNode
+		var dotModules []string
+		mainNode := overview.MainModules[0]
+		if dep != "" {
+			mainNode = dep
+		}
+		fileContents += getDOTCollapsedNodes(collapsed, mainNode)
+		fileContents += getDOTCollapsedNodes(components, mainNode)
+		if dep != "" {
+			seen := map[string]bool{dep: true}
+			dotModules = append(dotModules, dep)
+			for _, e := range focusEdges {
+				for _, m := range []string{e.From, e.To} {
+					if !seen[m] {
+						seen[m] = true
+						dotModules = append(dotModules, m)
+					}
+				}
+			}
+		} else {
+			for _, n := range nodes {
+				dotModules = append(dotModules, n.Module)
+			}
+		}
+		if annotateOutput {
+			annotations, err := newDOTAnnotations(overview, dotModules, attributesPath)
+			if err != nil {
+				return err
+			}
+			for _, m := range dotModules {
+				if m == mainNode {
+					fileContents += fmt.Sprintf("MainNode [%s]\n", annotations.nodeAttributes(m, "yellow"))
+				} else {
+					fileContents += fmt.Sprintf("\"%s\" [%s]\n", m, annotations.nodeAttributes(m, ""))
+				}
+			}
+		}
+		var clusters []graphCluster
+		if graphClusterBy != "" {
+			clusters = buildClusters(dotModules, overview.MainModules, graphClusterBy, graphClusterPatterns)
+			fileContents += getDOTClusters(clusters, mainNode)
+		}
 		fileContents += "}"
 		if graphJSONOutput {
 			edges := getEdges(overview.Graph)
@@ -121,6 +352,10 @@ var graphCmd = &cobra.Command{
 				EdgeObjects         []graphEdge         `json:"edgeObjects"`
 				Rankings            *graphRankings      `json:"rankings,omitempty"`
 				FocusedDependency   string              `json:"focusedDependency,omitempty"`
+				Clusters            []graphCluster      `json:"clusters,omitempty"`
+				Collapsed           []collapsedGroup    `json:"collapsed,omitempty"`
+				Components          []collapsedGroup    `json:"components,omitempty"`
+				Reduced             bool                `json:"reduced,omitempty"`
 				ShowEdgeTypes       bool                `json:"showEdgeTypes"`
 				DirectCount         int                 `json:"directDependencyCount"`
 				TransitiveCount     int                 `json:"transitiveDependencyCount"`
@@ -135,6 +370,10 @@ var graphCmd = &cobra.Command{
 				EdgeObjects:         edgeObjects,
 				Rankings:            rankings,
 				FocusedDependency:   dep,
+				Clusters:            clusters,
+				Collapsed:           collapsed,
+				Components:          components,
+				Reduced:             graphReduce,
 				ShowEdgeTypes:       showEdgeTypes,
 				DirectCount:         len(overview.DirectDepList),
 				TransitiveCount:     len(overview.TransDepList),
@@ -162,25 +401,6 @@ var graphCmd = &cobra.Command{
 	},
 }
 
-// find all possible chains starting from currentDep
-func getAllChains(currentDep string, graph map[string][]string, currentChain Chain, chains *[]Chain) {
-	currentChain = append(currentChain, currentDep)
-	_, ok := graph[currentDep]
-	if ok {
-		for _, dep := range graph[currentDep] {
-			if !contains(currentChain, dep) {
-				cpy := make(Chain, len(currentChain))
-				copy(cpy, currentChain)
-				getAllChains(dep, graph, cpy, chains)
-			} else {
-				*chains = append(*chains, currentChain)
-			}
-		}
-	} else {
-		*chains = append(*chains, currentChain)
-	}
-}
-
 // get the contents of the .dot file for the graph
 // when the --dep flag is set
 func getFileContentsForSingleDep(chains []Chain, dep string) string {
@@ -216,7 +436,10 @@ func getFileContentsForAllDepsWithTypes(overview *DependencyOverview, showTypes
 		return ""
 	}
 	// color the main module as yellow
-	data := colorMainNode(overview.MainModules[0])
+	data := ""
+	if mainModulesDrawn(overview) {
+		data = colorMainNode(overview.MainModules[0])
+	}
 
 	// Create a set of main modules for quick lookup
 	mainModSet := make(map[string]bool)
@@ -263,6 +486,153 @@ func getFileContentsForAllDepsWithTypes(overview *DependencyOverview, showTypes
 	return data
 }
 
+// getMermaidForSingleDep is the Mermaid equivalent of getFileContentsForFocus
+func getMermaidForSingleDep(edges []graphEdge, dep string) string {
+	g := newMermaidGraph("", "TD")
+	g.AddNode(dep, "", "main")
+	for _, e := range edges {
+		g.AddEdge(mermaidEdge{From: e.From, To: e.To})
+	}
+	return g.String()
+}
+
+// getMermaidForAllDepsWithTypes is the Mermaid equivalent of
+// getFileContentsForAllDepsWithTypes
+func getMermaidForAllDepsWithTypes(overview *DependencyOverview, showTypes bool) string {
+	g := newMermaidGraph("", "TD")
+	if len(overview.MainModules) == 0 {
+		return g.String()
+	}
+	if mainModulesDrawn(overview) {
+		g.AddNode(overview.MainModules[0], "", "main")
+	}
+
+	mainModSet := make(map[string]bool)
+	for _, m := range overview.MainModules {
+		mainModSet[m] = true
+	}
+
+	allDeps := getAllDeps(overview.DirectDepList, overview.TransDepList)
+	allDeps = append(allDeps, overview.MainModules[0])
+	sort.Strings(allDeps)
+
+	for _, dep := range allDeps {
+		for _, neighbour := range overview.Graph[dep] {
+			e := mermaidEdge{From: dep, To: neighbour}
+			if showTypes {
+				if mainModSet[dep] {
+					e.Style, e.Color = mermaidThick, "blue"
+				} else {
+					e.Style, e.Color = mermaidDotted, "gray"
+				}
+			}
+			g.AddEdge(e)
+		}
+	}
+	return g.String()
+}
+
+// getSVGForSingleDep renders the subgraph around dep as a self-contained SVG
+func getSVGForSingleDep(overview *DependencyOverview, focusEdges []graphEdge, dep string) string {
+	nodeSet := map[string]bool{dep: true}
+	nodes := []string{dep}
+	edges := make([]svgEdge, 0, len(focusEdges))
+	hasDependencies := false
+	for _, e := range focusEdges {
+		hasDependencies = hasDependencies || e.From == dep
+		for _, n := range []string{e.From, e.To} {
+			if !nodeSet[n] {
+				nodeSet[n] = true
+				nodes = append(nodes, n)
+			}
+		}
+		edges = append(edges, svgEdge{From: e.From, To: e.To})
+	}
+	var sinks []string
+	if !hasDependencies {
+		// Nothing below dep (e.g. --reverse): draw it at the bottom as in why.
+		sinks = []string{dep}
+	}
+	return renderSVGDiagram(svgDiagram{
+		Title:    fmt.Sprintf("Dependency graph around %s", dep),
+		Subtitle: fmt.Sprintf("%d modules, %d edges", len(nodes), len(edges)),
+		Nodes:    nodes,
+		Edges:    edges,
+		Layout:   layoutOptions{Roots: overview.MainModules, Sinks: sinks},
+		Legend:   svgDefaultLegend,
+		NodeStyle: func(node string) svgNodeStyle {
+			return svgNodeStyle{
+				Label: abbreviateModule(node, overview.MainModules),
+				Color: classifyNodeColor(node, dep, overview.MainModules),
+			}
+		},
+		EdgeStyle: func(e svgEdge, layerDiff int) svgEdgeStyle {
+			return svgEdgeStyle{Dashed: layerDiff > 1}
+		},
+	})
+}
+
+// getSVGForAllDeps renders the full dependency graph as a self-contained SVG.
+// With showTypes, direct edges are drawn solid blue and transitive edges
+// dashed gray, mirroring getFileContentsForAllDepsWithTypes.
+func getSVGForAllDeps(overview *DependencyOverview, nodes []graphNode, edges []graphEdge, showTypes bool) string {
+	mainModSet := make(map[string]bool)
+	for _, m := range overview.MainModules {
+		mainModSet[m] = true
+	}
+	modules := make([]string, 0, len(nodes))
+	for _, n := range nodes {
+		modules = append(modules, n.Module)
+	}
+	svgEdges := make([]svgEdge, 0, len(edges))
+	for _, e := range edges {
+		svgEdges = append(svgEdges, svgEdge{From: e.From, To: e.To})
+	}
+	legend := svgDefaultLegend[:3]
+	return renderSVGDiagram(svgDiagram{
+		Title:    "Dependency graph",
+		Subtitle: fmt.Sprintf("%d modules, %d edges", len(modules), len(svgEdges)),
+		Nodes:    modules,
+		Edges:    svgEdges,
+		Layout:   layoutOptions{Roots: overview.MainModules},
+		Legend:   legend,
+		NodeStyle: func(node string) svgNodeStyle {
+			s := svgNodeStyle{
+				Label: abbreviateModule(node, overview.MainModules),
+				Color: classifyNodeColor(node, "", overview.MainModules),
+			}
+			if mainModSet[node] {
+				s.StrokeWidth = "2"
+			}
+			return s
+		},
+		EdgeStyle: func(e svgEdge, layerDiff int) svgEdgeStyle {
+			if !showTypes {
+				return svgEdgeStyle{}
+			}
+			if mainModSet[e.From] {
+				return svgEdgeStyle{Stroke: "#1976D2", Width: "2"}
+			}
+			return svgEdgeStyle{Stroke: "#9E9E9E", Dashed: true}
+		},
+	})
+}
+
+// mainModulesDrawn reports whether the main modules are part of the graph.
+// --min-depth removes them together with their edges, and then the graph
+// must not draw a lone main module node.
+func mainModulesDrawn(overview *DependencyOverview) bool {
+	if len(overview.Graph) == 0 {
+		return true
+	}
+	for _, m := range overview.MainModules {
+		if _, ok := overview.Graph[m]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func chainContains(chain Chain, dep string) bool {
 	for _, d := range chain {
 		if d == dep {
@@ -319,6 +689,7 @@ func buildGraphTopology(overview *DependencyOverview) ([]graphNode, []graphEdge)
 		})
 	}
 	sort.Slice(nodes, func(i, j int) bool { return nodes[i].Module < nodes[j].Module })
+	computeCentrality(nodes, edges)
 	return nodes, edges
 }
 
@@ -350,25 +721,46 @@ func shortestDepthByModule(mainModules []string, graph map[string][]string) map[
 
 func buildRankings(nodes []graphNode, mode string, n int) *graphRankings {
 	r := &graphRankings{Mode: mode, N: n}
-	if mode == "in" || mode == "both" {
-		r.In = topNByMetric(nodes, n, "in")
-	}
-	if mode == "out" || mode == "both" {
-		r.Out = topNByMetric(nodes, n, "out")
+	switch mode {
+	case metricIn, metricOut, "both":
+		if mode == metricIn || mode == "both" {
+			r.In = topNByMetric(nodes, n, metricIn)
+		}
+		if mode == metricOut || mode == "both" {
+			r.Out = topNByMetric(nodes, n, metricOut)
+		}
+	case metricBetweenness:
+		r.Betweenness = topNByMetric(nodes, n, metricBetweenness)
+	case metricPageRank:
+		r.PageRank = topNByMetric(nodes, n, metricPageRank)
+	case metricDependents:
+		r.Dependents = topNByMetric(nodes, n, metricDependents)
 	}
 	return r
 }
 
+// metricValue returns the value nodes are ranked by for metric.
+func metricValue(node graphNode, metric string) float64 {
+	switch metric {
+	case metricIn:
+		return float64(node.InDegree)
+	case metricOut:
+		return float64(node.OutDegree)
+	case metricBetweenness:
+		return node.Betweenness
+	case metricPageRank:
+		return node.PageRank
+	case metricDependents:
+		return float64(node.Dependents)
+	}
+	return 0
+}
+
 func topNByMetric(nodes []graphNode, n int, metric string) []graphNode {
 	ranked := make([]graphNode, len(nodes))
 	copy(ranked, nodes)
 	sort.Slice(ranked, func(i, j int) bool {
-		var left, right int
-		if metric == "in" {
-			left, right = ranked[i].InDegree, ranked[j].InDegree
-		} else {
-			left, right = ranked[i].OutDegree, ranked[j].OutDegree
-		}
+		left, right := metricValue(ranked[i], metric), metricValue(ranked[j], metric)
 		if left == right {
 			return ranked[i].Module < ranked[j].Module
 		}
@@ -381,47 +773,38 @@ func topNByMetric(nodes []graphNode, n int, metric string) []graphNode {
 }
 
 func printTopNodes(nodes []graphNode, mode string, n int) {
-	switch mode {
-	case "in":
-		printTopByMetric(nodes, n, "in")
-	case "out":
-		printTopByMetric(nodes, n, "out")
-	case "both":
-		printTopByMetric(nodes, n, "in")
+	if mode == "both" {
+		printTopByMetric(nodes, n, metricIn)
 		fmt.Println()
-		printTopByMetric(nodes, n, "out")
+		printTopByMetric(nodes, n, metricOut)
+		return
 	}
+	printTopByMetric(nodes, n, mode)
 }
 
 func printTopByMetric(nodes []graphNode, n int, metric string) {
-	ranked := make([]graphNode, len(nodes))
-	copy(ranked, nodes)
-	sort.Slice(ranked, func(i, j int) bool {
-		var left, right int
-		if metric == "in" {
-			left, right = ranked[i].InDegree, ranked[j].InDegree
-		} else {
-			left, right = ranked[i].OutDegree, ranked[j].OutDegree
-		}
-		if left == right {
-			return ranked[i].Module < ranked[j].Module
-		}
-		return left > right
-	})
-	if n > len(ranked) {
-		n = len(ranked)
+	ranked := topNByMetric(nodes, n, metric)
+
+	titles := map[string]string{
+		metricIn:          "Top by in-degree",
+		metricOut:         "Top by out-degree",
+		metricBetweenness: "Top by betweenness centrality",
+		metricPageRank:    "Top by PageRank",
+		metricDependents:  "Top by transitive dependents",
 	}
-
-	title := "Top by in-degree"
-	if metric == "out" {
-		title = "Top by out-degree"
-	}
-	fmt.Printf("%s (N=%d)\n", title, n)
+	fmt.Printf("%s (N=%d)\n", titles[metric], len(ranked))
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintln(w, "RANK\tMODULE\tIN\tOUT\tDEPTH\tMAIN")
-	for i := 0; i < n; i++ {
-		node := ranked[i]
-		fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%t\n", i+1, node.Module, node.InDegree, node.OutDegree, node.Depth, node.IsMainModule)
+	switch metric {
+	case metricBetweenness, metricPageRank, metricDependents:
+		fmt.Fprintln(w, "RANK\tMODULE\tDEPENDENTS\tBETWEENNESS\tPAGERANK\tIN\tOUT\tDEPTH\tMAIN")
+		for i, node := range ranked {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%.4f\t%.4f\t%d\t%d\t%d\t%t\n", i+1, node.Module, node.Dependents, node.Betweenness, node.PageRank, node.InDegree, node.OutDegree, node.Depth, node.IsMainModule)
+		}
+	default:
+		fmt.Fprintln(w, "RANK\tMODULE\tIN\tOUT\tDEPTH\tMAIN")
+		for i, node := range ranked {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%d\t%d\t%t\n", i+1, node.Module, node.InDegree, node.OutDegree, node.Depth, node.IsMainModule)
+		}
 	}
 	_ = w.Flush()
 }
@@ -430,10 +813,25 @@ func init() {
 	rootCmd.AddCommand(graphCmd)
 	graphCmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory containing the module to evaluate. Defaults to the current directory.")
 	graphCmd.Flags().StringVarP(&dep, "dep", "p", "", "Specify dependency to create a graph around")
+	graphCmd.Flags().BoolVar(&graphDescendants, "descendants", true, "With --dep, also include the modules reachable from the dependency")
+	graphCmd.Flags().BoolVar(&graphReverse, "reverse", false, "With --dep, graph every module that depends on the dependency instead")
+	graphCmd.Flags().IntVar(&graphMaxDepth, "max-depth", -1, "Drop modules deeper than N edges from the main modules (from the dependency with --reverse)")
+	graphCmd.Flags().IntVar(&graphMinDepth, "min-depth", 0, "Drop modules closer than N edges to the main modules")
 	graphCmd.Flags().BoolVar(&showEdgeTypes, "show-edge-types", false, "Distinguish direct vs transitive edges with colors/styles")
 	graphCmd.Flags().BoolVar(&graphDotOutput, "dot", false, "Output DOT graph to stdout")
 	graphCmd.Flags().BoolVarP(&graphJSONOutput, "json", "j", false, "Output graph data in JSON format")
-	graphCmd.Flags().StringVar(&graphTopMode, "top", "", "Show top modules by degree: in, out, or both")
+	graphCmd.Flags().BoolVar(&graphMermaidOutput, "mermaid", false, "Output Mermaid flowchart to stdout")
+	graphCmd.Flags().BoolVar(&graphSVGOutput, "svg", false, "Output self-contained SVG diagram to stdout (no Graphviz needed)")
+	graphCmd.Flags().StringVar(&graphFormat, "format", "", "Export graph to stdout in another format: graphml or gexf")
+	graphCmd.Flags().StringVar(&graphHTMLPath, "html", "", "Write a self-contained interactive HTML explorer to this path")
+	graphCmd.Flags().StringVar(&graphClusterBy, "cluster-by", "", "Group modules into DOT clusters by path prefix: domain, org or pattern")
+	graphCmd.Flags().StringSliceVar(&graphClusterPatterns, "cluster-pattern", []string{}, "Module path pattern defining a cluster for --cluster-by pattern (repeatable, supports * wildcard)")
+	graphCmd.Flags().StringSliceVar(&graphCollapse, "collapse", []string{}, "Merge all modules matching a pattern into a single node (repeatable, supports * wildcard)")
+	graphCmd.Flags().BoolVar(&annotateOutput, "annotate", false, "Label DOT nodes with versions, mark main modules and colour test-only modules")
+	graphCmd.Flags().StringVar(&attributesPath, "attributes", "", "With --annotate, JSON file mapping modules to a fill colour and extra label")
+	graphCmd.Flags().BoolVar(&graphReduce, "reduce", false, "Drop edges implied by longer paths (transitive reduction)")
+	graphCmd.Flags().BoolVar(&graphCondense, "condense", false, "Merge each cycle (strongly connected component) into a single node")
+	graphCmd.Flags().StringVar(&graphTopMode, "top", "", "Show top modules by metric: in, out, both, betweenness, pagerank or dependents")
 	graphCmd.Flags().IntVarP(&graphTopN, "n", "n", 10, "Number of modules to show with --top")
 	graphCmd.Flags().StringSliceVar(&excludeModules, "exclude-modules", []string{}, "Exclude module path patterns (repeatable, supports * wildcard)")
 	graphCmd.Flags().StringVar(&graphOutputPath, "output", "graph.dot", "Path to DOT output file when not using --dot or --json")
@@ -0,0 +1,193 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "math"
+
+// Ranking metrics accepted by --top, besides "both".
+const (
+	metricIn          = "in"
+	metricOut         = "out"
+	metricBetweenness = "betweenness"
+	metricPageRank    = "pagerank"
+	metricDependents  = "dependents"
+)
+
+const (
+	pageRankDamping    = 0.85
+	pageRankIterations = 100
+	pageRankTolerance  = 1e-12
+)
+
+// indexedGraph is an adjacency list over node indexes, used by the
+// centrality computations to avoid map lookups in their inner loops.
+type indexedGraph struct {
+	out [][]int
+	in  [][]int
+}
+
+// newIndexedGraph indexes edges by the position of their endpoints in nodes.
+// Nodes and edges must be sorted, as returned by buildGraphTopology, so that
+// floating point sums are accumulated in a deterministic order.
+func newIndexedGraph(nodes []graphNode, edges []graphEdge) indexedGraph {
+	index := make(map[string]int, len(nodes))
+	for i, n := range nodes {
+		index[n.Module] = i
+	}
+	g := indexedGraph{out: make([][]int, len(nodes)), in: make([][]int, len(nodes))}
+	for _, e := range edges {
+		from, to := index[e.From], index[e.To]
+		g.out[from] = append(g.out[from], to)
+		g.in[to] = append(g.in[to], from)
+	}
+	return g
+}
+
+// computeCentrality fills in the betweenness, PageRank and dependents count
+// of every node.
+func computeCentrality(nodes []graphNode, edges []graphEdge) {
+	g := newIndexedGraph(nodes, edges)
+	betweenness := betweennessCentrality(g)
+	pageRank := pageRankScores(g)
+	dependents := dependentCounts(g)
+	for i := range nodes {
+		nodes[i].Betweenness = betweenness[i]
+		nodes[i].PageRank = pageRank[i]
+		nodes[i].Dependents = dependents[i]
+	}
+}
+
+// betweennessCentrality returns the fraction of shortest paths between other
+// pairs of modules that pass through each module, using Brandes' algorithm
+// and normalised by (n-1)(n-2) for directed graphs.
+func betweennessCentrality(g indexedGraph) []float64 {
+	n := len(g.out)
+	centrality := make([]float64, n)
+	sigma := make([]float64, n)
+	dist := make([]int, n)
+	delta := make([]float64, n)
+	preds := make([][]int, n)
+	stack := make([]int, 0, n)
+	queue := make([]int, 0, n)
+
+	for s := 0; s < n; s++ {
+		for i := 0; i < n; i++ {
+			sigma[i], dist[i], delta[i] = 0, -1, 0
+			preds[i] = preds[i][:0]
+		}
+		sigma[s], dist[s] = 1, 0
+		stack, queue = stack[:0], append(queue[:0], s)
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			stack = append(stack, v)
+			for _, w := range g.out[v] {
+				if dist[w] < 0 {
+					dist[w] = dist[v] + 1
+					queue = append(queue, w)
+				}
+				if dist[w] == dist[v]+1 {
+					sigma[w] += sigma[v]
+					preds[w] = append(preds[w], v)
+				}
+			}
+		}
+		for i := len(stack) - 1; i >= 0; i-- {
+			w := stack[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				centrality[w] += delta[w]
+			}
+		}
+	}
+	if n > 2 {
+		scale := 1 / float64((n-1)*(n-2))
+		for i := range centrality {
+			centrality[i] *= scale
+		}
+	}
+	return centrality
+}
+
+// pageRankScores runs PageRank along dependency edges, so modules that are
+// required by many (important) modules score highest. Modules without
+// dependencies spread their rank evenly over the graph.
+func pageRankScores(g indexedGraph) []float64 {
+	n := len(g.out)
+	if n == 0 {
+		return nil
+	}
+	rank := make([]float64, n)
+	next := make([]float64, n)
+	for i := range rank {
+		rank[i] = 1 / float64(n)
+	}
+	for iter := 0; iter < pageRankIterations; iter++ {
+		dangling := 0.0
+		for v := 0; v < n; v++ {
+			if len(g.out[v]) == 0 {
+				dangling += rank[v]
+			}
+		}
+		base := (1-pageRankDamping)/float64(n) + pageRankDamping*dangling/float64(n)
+		diff := 0.0
+		for v := 0; v < n; v++ {
+			sum := 0.0
+			for _, u := range g.in[v] {
+				sum += rank[u] / float64(len(g.out[u]))
+			}
+			next[v] = base + pageRankDamping*sum
+			diff += math.Abs(next[v] - rank[v])
+		}
+		rank, next = next, rank
+		if diff < pageRankTolerance {
+			break
+		}
+	}
+	return rank
+}
+
+// dependentCounts returns the size of each module's reverse transitive
+// closure: the number of other modules that depend on it directly or
+// transitively.
+func dependentCounts(g indexedGraph) []int {
+	n := len(g.in)
+	counts := make([]int, n)
+	seen := make([]int, n)
+	for i := range seen {
+		seen[i] = -1
+	}
+	queue := make([]int, 0, n)
+	for s := 0; s < n; s++ {
+		seen[s] = s
+		queue = append(queue[:0], s)
+		for len(queue) > 0 {
+			v := queue[0]
+			queue = queue[1:]
+			for _, u := range g.in[v] {
+				if seen[u] != s {
+					seen[u] = s
+					counts[s]++
+					queue = append(queue, u)
+				}
+			}
+		}
+	}
+	return counts
+}
@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"math"
+	"testing"
+)
+
+func centralityTestNodes() map[string]graphNode {
+	// G is a bridge: every path from the top half to P and Q runs through it,
+	// although it has the same in-degree as P.
+	overview := &DependencyOverview{
+		MainModules: []string{"main"},
+		Graph: map[string][]string{
+			"main": {"X", "Y"},
+			"X":    {"G"},
+			"Y":    {"G"},
+			"G":    {"P", "Q"},
+		},
+	}
+	nodes, _ := buildGraphTopology(overview)
+	byModule := map[string]graphNode{}
+	for _, n := range nodes {
+		byModule[n.Module] = n
+	}
+	return byModule
+}
+
+func Test_betweennessCentrality(t *testing.T) {
+	nodes := centralityTestNodes()
+	// n = 6, so scores are normalised by 5*4 = 20.
+	want := map[string]float64{"main": 0, "X": 1.5 / 20, "Y": 1.5 / 20, "G": 6.0 / 20, "P": 0, "Q": 0}
+	for module, w := range want {
+		if got := nodes[module].Betweenness; math.Abs(got-w) > 1e-9 {
+			t.Errorf("betweenness[%s] = %v, want %v", module, got, w)
+		}
+	}
+}
+
+func Test_dependentCounts(t *testing.T) {
+	nodes := centralityTestNodes()
+	want := map[string]int{"main": 0, "X": 1, "Y": 1, "G": 3, "P": 4, "Q": 4}
+	for module, w := range want {
+		if got := nodes[module].Dependents; got != w {
+			t.Errorf("dependents[%s] = %d, want %d", module, got, w)
+		}
+	}
+
+	// Members of a cycle do not count themselves.
+	g := newIndexedGraph(
+		[]graphNode{{Module: "A"}, {Module: "B"}},
+		[]graphEdge{{From: "A", To: "B"}, {From: "B", To: "A"}},
+	)
+	if got := dependentCounts(g); got[0] != 1 || got[1] != 1 {
+		t.Errorf("dependentCounts on a cycle = %v, want [1 1]", got)
+	}
+}
+
+func Test_pageRankScores(t *testing.T) {
+	nodes := centralityTestNodes()
+	total := 0.0
+	for _, n := range nodes {
+		total += n.PageRank
+	}
+	if math.Abs(total-1) > 1e-9 {
+		t.Errorf("PageRank should sum to 1, got %v", total)
+	}
+	if !(nodes["G"].PageRank > nodes["X"].PageRank && nodes["X"].PageRank > nodes["main"].PageRank) {
+		t.Errorf("unexpected PageRank order: G=%v X=%v main=%v", nodes["G"].PageRank, nodes["X"].PageRank, nodes["main"].PageRank)
+	}
+	if nodes["P"].PageRank != nodes["Q"].PageRank {
+		t.Errorf("symmetric modules should have equal PageRank")
+	}
+}
+
+func Test_buildRankings_centrality(t *testing.T) {
+	byModule := centralityTestNodes()
+	nodes := make([]graphNode, 0, len(byModule))
+	for _, n := range byModule {
+		nodes = append(nodes, n)
+	}
+	r := buildRankings(nodes, metricBetweenness, 1)
+	if len(r.Betweenness) != 1 || r.Betweenness[0].Module != "G" || r.In != nil {
+		t.Errorf("betweenness ranking = %+v", r)
+	}
+	r = buildRankings(nodes, metricDependents, 2)
+	if len(r.Dependents) != 2 || r.Dependents[0].Module != "P" || r.Dependents[1].Module != "Q" {
+		t.Errorf("dependents ranking = %+v", r.Dependents)
+	}
+	r = buildRankings(nodes, metricPageRank, 6)
+	if len(r.PageRank) != 6 || r.PageRank[5].Module != "main" {
+		t.Errorf("pagerank ranking = %+v", r.PageRank)
+	}
+}
@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	clusterByDomain  = "domain"
+	clusterByOrg     = "org"
+	clusterByPattern = "pattern"
+)
+
+// graphCluster is a group of modules drawn together with --cluster-by.
+type graphCluster struct {
+	Name    string   `json:"name"`
+	Members []string `json:"members"`
+}
+
+// collapsedGroup is a single node standing in for several modules: those
+// matching a --collapse pattern, or the members of a cycle with --condense.
+type collapsedGroup struct {
+	Node        string   `json:"node"`
+	MemberCount int      `json:"memberCount"`
+	Members     []string `json:"members"`
+	// MainModules lists the members that are main modules, which only
+	// --condense merges.
+	MainModules []string `json:"mainModules,omitempty"`
+}
+
+// clusterKey returns the name of the cluster module belongs to, or "" if it
+// belongs to none. domain groups by host (github.com), org by host and first
+// path element (github.com/aws) and pattern by the first matching pattern.
+func clusterKey(module, mode string, patterns []string) string {
+	parts := strings.Split(module, "/")
+	switch mode {
+	case clusterByDomain:
+		return parts[0]
+	case clusterByOrg:
+		if len(parts) < 2 {
+			return module
+		}
+		return parts[0] + "/" + parts[1]
+	case clusterByPattern:
+		for _, pattern := range patterns {
+			if matchModulePattern(module, pattern) {
+				return pattern
+			}
+		}
+	}
+	return ""
+}
+
+// buildClusters groups modules by clusterKey. Main modules are never
+// clustered, and clusters with a single member are dropped since they would
+// only add a box around one node.
+func buildClusters(modules []string, mainModules []string, mode string, patterns []string) []graphCluster {
+	mainSet := map[string]bool{}
+	for _, m := range mainModules {
+		mainSet[m] = true
+	}
+	members := map[string][]string{}
+	for _, m := range modules {
+		if mainSet[m] {
+			continue
+		}
+		if key := clusterKey(m, mode, patterns); key != "" {
+			members[key] = append(members[key], m)
+		}
+	}
+	clusters := []graphCluster{}
+	for name, list := range members {
+		if len(list) < 2 {
+			continue
+		}
+		sort.Strings(list)
+		clusters = append(clusters, graphCluster{Name: name, Members: list})
+	}
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
+	return clusters
+}
+
+// getDOTClusters returns a Graphviz subgraph cluster block per cluster.
+// mainNode is the module that is written as MainNode in the DOT output.
+func getDOTClusters(clusters []graphCluster, mainNode string) string {
+	var b strings.Builder
+	for i, c := range clusters {
+		fmt.Fprintf(&b, "subgraph cluster_%d {\nlabel=\"%s\";\nstyle=\"rounded,dashed\";\ncolor=\"gray50\";\n", i, c.Name)
+		for _, m := range c.Members {
+			if m == mainNode {
+				b.WriteString("MainNode;\n")
+			} else {
+				fmt.Fprintf(&b, "\"%s\";\n", m)
+			}
+		}
+		b.WriteString("}\n")
+	}
+	return b.String()
+}
+
+// getDOTCollapsedNodes labels each collapsed node with its member count.
+// mainNode is the node drawn as MainNode.
+func getDOTCollapsedNodes(groups []collapsedGroup, mainNode string) string {
+	var b strings.Builder
+	for _, g := range groups {
+		name := "\"" + g.Node + "\""
+		if g.Node == mainNode {
+			name = "MainNode"
+		}
+		fmt.Fprintf(&b, "%s [label=\"%s\\n(%d modules)\", shape=\"box3d\"]\n", name, g.Node, g.MemberCount)
+	}
+	return b.String()
+}
+
+// collapseModules merges every module matching one of patterns into a single
+// node named after the pattern. Main modules are never collapsed.
+func collapseModules(overview *DependencyOverview, patterns []string) (*DependencyOverview, []collapsedGroup) {
+	mainSet := map[string]bool{}
+	for _, m := range overview.MainModules {
+		mainSet[m] = true
+	}
+	rep := map[string]string{}
+	membersByPattern := map[string][]string{}
+	for _, m := range graphModules(overview) {
+		if mainSet[m] {
+			continue
+		}
+		for _, pattern := range patterns {
+			if matchModulePattern(m, pattern) {
+				rep[m] = pattern
+				membersByPattern[pattern] = append(membersByPattern[pattern], m)
+				break
+			}
+		}
+	}
+
+	groups := []collapsedGroup{}
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		members := membersByPattern[pattern]
+		if len(members) == 0 || seen[pattern] {
+			continue
+		}
+		seen[pattern] = true
+		groups = append(groups, collapsedGroup{Node: pattern, MemberCount: len(members), Members: members})
+	}
+	return mergeModules(overview, rep), groups
+}
+
+// graphModules returns every module in the overview's graph, sorted.
+func graphModules(overview *DependencyOverview) []string {
+	set := map[string]bool{}
+	for _, m := range overview.MainModules {
+		set[m] = true
+	}
+	for from, tos := range overview.Graph {
+		set[from] = true
+		for _, to := range tos {
+			set[to] = true
+		}
+	}
+	modules := make([]string, 0, len(set))
+	for m := range set {
+		modules = append(modules, m)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// mergeModules returns a copy of overview in which every module in rep is
+// replaced by the node it maps to. Edges are rewritten to the merged nodes,
+// dropping the ones internal to a group and duplicates. Merged modules lose
+// their version. Main modules mapped to a node make that node a main module.
+func mergeModules(overview *DependencyOverview, rep map[string]string) *DependencyOverview {
+	node := func(m string) string {
+		if r, ok := rep[m]; ok {
+			return r
+		}
+		return m
+	}
+
+	froms := make([]string, 0, len(overview.Graph))
+	for from := range overview.Graph {
+		froms = append(froms, from)
+	}
+	sort.Strings(froms)
+
+	graph := map[string][]string{}
+	seen := map[string]bool{}
+	for _, from := range froms {
+		f := node(from)
+		for _, to := range overview.Graph[from] {
+			t := node(to)
+			if f == t || seen[f+" "+t] {
+				continue
+			}
+			seen[f+" "+t] = true
+			graph[f] = append(graph[f], t)
+		}
+	}
+
+	// main modules keep their order, since the first one is drawn as the
+	// main node
+	mainModules := []string{}
+	mainSeen := map[string]bool{}
+	for _, m := range overview.MainModules {
+		r := node(m)
+		if !mainSeen[r] {
+			mainSeen[r] = true
+			mainModules = append(mainModules, r)
+		}
+	}
+	mapList := func(list []string) []string {
+		out := []string{}
+		listSeen := map[string]bool{}
+		for _, m := range list {
+			r := node(m)
+			if !listSeen[r] && !mainSeen[r] {
+				listSeen[r] = true
+				out = append(out, r)
+			}
+		}
+		sort.Strings(out)
+		return out
+	}
+
+	merged := *overview
+	merged.Graph = graph
+	merged.MainModules = mainModules
+	merged.DirectDepList = mapList(overview.DirectDepList)
+	merged.TransDepList = mapList(overview.TransDepList)
+	merged.Versions = map[string]string{}
+	for m, v := range overview.Versions {
+		if _, ok := rep[m]; !ok {
+			merged.Versions[m] = v
+		}
+	}
+	return &merged
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_clusterKey(t *testing.T) {
+	tests := []struct {
+		module, mode string
+		patterns     []string
+		want         string
+	}{
+		{"github.com/aws/aws-sdk-go-v2/service/s3", clusterByDomain, nil, "github.com"},
+		{"github.com/aws/aws-sdk-go-v2/service/s3", clusterByOrg, nil, "github.com/aws"},
+		{"go", clusterByOrg, nil, "go"},
+		{"k8s.io/api", clusterByPattern, []string{"sigs.k8s.io/*", "k8s.io/*"}, "k8s.io/*"},
+		{"example.com/x", clusterByPattern, []string{"k8s.io/*"}, ""},
+	}
+	for _, tt := range tests {
+		if got := clusterKey(tt.module, tt.mode, tt.patterns); got != tt.want {
+			t.Errorf("clusterKey(%q, %q) = %q, want %q", tt.module, tt.mode, got, tt.want)
+		}
+	}
+}
+
+func Test_buildClusters(t *testing.T) {
+	modules := []string{"main", "github.com/a/x", "github.com/a/y", "github.com/b/z", "k8s.io/api"}
+	clusters := buildClusters(modules, []string{"main"}, clusterByOrg, nil)
+	want := []graphCluster{{Name: "github.com/a", Members: []string{"github.com/a/x", "github.com/a/y"}}}
+	if !reflect.DeepEqual(clusters, want) {
+		t.Fatalf("buildClusters = %+v, want %+v", clusters, want)
+	}
+
+	dot := getDOTClusters(clusters, "github.com/a/x")
+	if !strings.Contains(dot, "subgraph cluster_0 {") || !strings.Contains(dot, "label=\"github.com/a\";") {
+		t.Errorf("missing cluster header:\n%s", dot)
+	}
+	if !strings.Contains(dot, "MainNode;\n") || !strings.Contains(dot, "\"github.com/a/y\";\n") {
+		t.Errorf("missing cluster members:\n%s", dot)
+	}
+}
+
+func Test_collapseModules(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules:   []string{"main"},
+		DirectDepList: []string{"aws/s3", "aws/sts", "other"},
+		TransDepList:  []string{"aws/core", "lib"},
+		Graph: map[string][]string{
+			"main":    {"aws/s3", "aws/sts", "other"},
+			"aws/s3":  {"aws/core", "lib"},
+			"aws/sts": {"aws/core", "lib"},
+			"other":   {"aws/core"},
+		},
+		Versions: map[string]string{"aws/s3": "v1.0.0", "lib": "v0.1.0"},
+	}
+	collapsed, groups := collapseModules(overview, []string{"aws/*"})
+
+	wantGraph := map[string][]string{
+		"main":  {"aws/*", "other"},
+		"aws/*": {"lib"},
+		"other": {"aws/*"},
+	}
+	if !reflect.DeepEqual(collapsed.Graph, wantGraph) {
+		t.Errorf("collapsed graph = %v, want %v", collapsed.Graph, wantGraph)
+	}
+	if !reflect.DeepEqual(collapsed.DirectDepList, []string{"aws/*", "other"}) {
+		t.Errorf("DirectDepList = %v", collapsed.DirectDepList)
+	}
+	if _, ok := collapsed.Versions["aws/s3"]; ok {
+		t.Error("collapsed modules should not keep a version")
+	}
+	wantGroups := []collapsedGroup{{Node: "aws/*", MemberCount: 3, Members: []string{"aws/core", "aws/s3", "aws/sts"}}}
+	if !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("groups = %+v, want %+v", groups, wantGroups)
+	}
+	if len(overview.Graph["main"]) != 3 {
+		t.Error("the original overview must not be modified")
+	}
+	if dot := getDOTCollapsedNodes(groups, "main"); !strings.Contains(dot, `"aws/*" [label="aws/*\n(3 modules)"`) {
+		t.Errorf("unexpected collapsed node DOT: %s", dot)
+	}
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	graphFormatGraphML = "graphml"
+	graphFormatGEXF    = "gexf"
+)
+
+// exportNode is a graphNode with the extra attributes written by the
+// GraphML, GEXF and HTML exporters.
+type exportNode struct {
+	graphNode
+	Version  string `json:"version"`
+	TestOnly bool   `json:"testOnly"`
+}
+
+// exportEdge is a graphEdge typed as direct (from a main module) or transitive.
+type exportEdge struct {
+	graphEdge
+	Type string `json:"type"`
+}
+
+// buildExportGraph decorates the output of buildGraphTopology with versions,
+// test-only status and edge types. Node and edge order is preserved, so the
+// result is deterministic.
+func buildExportGraph(overview *DependencyOverview, nodes []graphNode, edges []graphEdge, testOnlySet map[string]bool) ([]exportNode, []exportEdge) {
+	mainSet := map[string]bool{}
+	for _, m := range overview.MainModules {
+		mainSet[m] = true
+	}
+	exportNodes := make([]exportNode, 0, len(nodes))
+	for _, n := range nodes {
+		exportNodes = append(exportNodes, exportNode{
+			graphNode: n,
+			Version:   overview.Versions[n.Module],
+			TestOnly:  testOnlySet[n.Module],
+		})
+	}
+	exportEdges := make([]exportEdge, 0, len(edges))
+	for _, e := range edges {
+		edgeType := "transitive"
+		if mainSet[e.From] {
+			edgeType = "direct"
+		}
+		exportEdges = append(exportEdges, exportEdge{graphEdge: e, Type: edgeType})
+	}
+	return exportNodes, exportEdges
+}
+
+func renderGraphML(nodes []exportNode, edges []exportEdge) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<graphml xmlns="http://graphml.graphdrawing.org/xmlns" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://graphml.graphdrawing.org/xmlns http://graphml.graphdrawing.org/xmlns/1.0/graphml.xsd">
+  <key id="label" for="node" attr.name="label" attr.type="string"/>
+  <key id="version" for="node" attr.name="version" attr.type="string"/>
+  <key id="depth" for="node" attr.name="depth" attr.type="int"/>
+  <key id="inDegree" for="node" attr.name="inDegree" attr.type="int"/>
+  <key id="outDegree" for="node" attr.name="outDegree" attr.type="int"/>
+  <key id="isMainModule" for="node" attr.name="isMainModule" attr.type="boolean"/>
+  <key id="testOnly" for="node" attr.name="testOnly" attr.type="boolean"/>
+  <key id="type" for="edge" attr.name="type" attr.type="string"/>
+  <graph id="depstat" edgedefault="directed">
+`)
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "    <node id=\"%s\">\n", xmlEscape(n.Module))
+		fmt.Fprintf(&b, "      <data key=\"label\">%s</data>\n", xmlEscape(n.Module))
+		fmt.Fprintf(&b, "      <data key=\"version\">%s</data>\n", xmlEscape(n.Version))
+		fmt.Fprintf(&b, "      <data key=\"depth\">%d</data>\n", n.Depth)
+		fmt.Fprintf(&b, "      <data key=\"inDegree\">%d</data>\n", n.InDegree)
+		fmt.Fprintf(&b, "      <data key=\"outDegree\">%d</data>\n", n.OutDegree)
+		fmt.Fprintf(&b, "      <data key=\"isMainModule\">%t</data>\n", n.IsMainModule)
+		fmt.Fprintf(&b, "      <data key=\"testOnly\">%t</data>\n", n.TestOnly)
+		b.WriteString("    </node>\n")
+	}
+	for i, e := range edges {
+		fmt.Fprintf(&b, "    <edge id=\"e%d\" source=\"%s\" target=\"%s\">\n", i, xmlEscape(e.From), xmlEscape(e.To))
+		fmt.Fprintf(&b, "      <data key=\"type\">%s</data>\n", e.Type)
+		b.WriteString("    </edge>\n")
+	}
+	b.WriteString("  </graph>\n</graphml>\n")
+	return b.String()
+}
+
+func renderGEXF(nodes []exportNode, edges []exportEdge) string {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://gexf.net/1.3 http://gexf.net/1.3/gexf.xsd" version="1.3">
+  <meta>
+    <creator>depstat</creator>
+    <description>Go module dependency graph</description>
+  </meta>
+  <graph mode="static" defaultedgetype="directed">
+    <attributes class="node">
+      <attribute id="version" title="version" type="string"/>
+      <attribute id="depth" title="depth" type="integer"/>
+      <attribute id="inDegree" title="inDegree" type="integer"/>
+      <attribute id="outDegree" title="outDegree" type="integer"/>
+      <attribute id="isMainModule" title="isMainModule" type="boolean"/>
+      <attribute id="testOnly" title="testOnly" type="boolean"/>
+    </attributes>
+    <attributes class="edge">
+      <attribute id="type" title="type" type="string"/>
+    </attributes>
+    <nodes>
+`)
+	for _, n := range nodes {
+		fmt.Fprintf(&b, "      <node id=\"%s\" label=\"%s\">\n", xmlEscape(n.Module), xmlEscape(n.Module))
+		b.WriteString("        <attvalues>\n")
+		fmt.Fprintf(&b, "          <attvalue for=\"version\" value=\"%s\"/>\n", xmlEscape(n.Version))
+		fmt.Fprintf(&b, "          <attvalue for=\"depth\" value=\"%d\"/>\n", n.Depth)
+		fmt.Fprintf(&b, "          <attvalue for=\"inDegree\" value=\"%d\"/>\n", n.InDegree)
+		fmt.Fprintf(&b, "          <attvalue for=\"outDegree\" value=\"%d\"/>\n", n.OutDegree)
+		fmt.Fprintf(&b, "          <attvalue for=\"isMainModule\" value=\"%t\"/>\n", n.IsMainModule)
+		fmt.Fprintf(&b, "          <attvalue for=\"testOnly\" value=\"%t\"/>\n", n.TestOnly)
+		b.WriteString("        </attvalues>\n")
+		b.WriteString("      </node>\n")
+	}
+	b.WriteString("    </nodes>\n    <edges>\n")
+	for i, e := range edges {
+		fmt.Fprintf(&b, "      <edge id=\"%d\" source=\"%s\" target=\"%s\" label=\"%s\">\n", i, xmlEscape(e.From), xmlEscape(e.To), e.Type)
+		b.WriteString("        <attvalues>\n")
+		fmt.Fprintf(&b, "          <attvalue for=\"type\" value=\"%s\"/>\n", e.Type)
+		b.WriteString("        </attvalues>\n")
+		b.WriteString("      </edge>\n")
+	}
+	b.WriteString("    </edges>\n  </graph>\n</gexf>\n")
+	return b.String()
+}
@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func exportTestGraph() ([]exportNode, []exportEdge) {
+	overview := &DependencyOverview{
+		MainModules:   []string{"main"},
+		DirectDepList: []string{"A", "B"},
+		TransDepList:  []string{"C"},
+		Graph: map[string][]string{
+			"main": {"A", "B"},
+			"A":    {"C"},
+			"B":    {"C"},
+		},
+		Versions: map[string]string{"A": "v1.0.0", "B": "v2.0.0", "C": "v0.1.0+incompatible"},
+	}
+	nodes, edges := buildGraphTopology(overview)
+	return buildExportGraph(overview, nodes, edges, map[string]bool{"B": true})
+}
+
+func Test_buildExportGraph(t *testing.T) {
+	nodes, edges := exportTestGraph()
+	for _, n := range nodes {
+		if n.Module == "B" && (!n.TestOnly || n.Version != "v2.0.0") {
+			t.Errorf("unexpected attributes for B: %+v", n)
+		}
+		if n.Module == "A" && n.TestOnly {
+			t.Errorf("A should not be test-only")
+		}
+	}
+	types := map[string]string{}
+	for _, e := range edges {
+		types[e.From+" -> "+e.To] = e.Type
+	}
+	if types["main -> A"] != "direct" || types["A -> C"] != "transitive" {
+		t.Fatalf("unexpected edge types: %v", types)
+	}
+}
+
+func Test_renderGraphML(t *testing.T) {
+	nodes, edges := exportTestGraph()
+	out := renderGraphML(nodes, edges)
+	if out != renderGraphML(nodes, edges) {
+		t.Fatal("GraphML output is not deterministic")
+	}
+
+	var doc struct {
+		Keys []struct {
+			ID string `xml:"id,attr"`
+		} `xml:"key"`
+		Graph struct {
+			Nodes []struct {
+				ID   string `xml:"id,attr"`
+				Data []struct {
+					Key   string `xml:"key,attr"`
+					Value string `xml:",chardata"`
+				} `xml:"data"`
+			} `xml:"node"`
+			Edges []struct {
+				Source string `xml:"source,attr"`
+				Target string `xml:"target,attr"`
+			} `xml:"edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("GraphML is not well-formed: %v\n%s", err, out)
+	}
+	if len(doc.Keys) != 8 || len(doc.Graph.Nodes) != 4 || len(doc.Graph.Edges) != 4 {
+		t.Fatalf("unexpected GraphML shape: %d keys, %d nodes, %d edges", len(doc.Keys), len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	for _, n := range doc.Graph.Nodes {
+		if n.ID != "C" {
+			continue
+		}
+		data := map[string]string{}
+		for _, d := range n.Data {
+			data[d.Key] = d.Value
+		}
+		if data["version"] != "v0.1.0+incompatible" || data["depth"] != "2" || data["inDegree"] != "2" || data["testOnly"] != "false" {
+			t.Errorf("unexpected data for C: %v", data)
+		}
+	}
+}
+
+func Test_renderGEXF(t *testing.T) {
+	nodes, edges := exportTestGraph()
+	out := renderGEXF(nodes, edges)
+	if out != renderGEXF(nodes, edges) {
+		t.Fatal("GEXF output is not deterministic")
+	}
+	var doc struct {
+		Version string `xml:"version,attr"`
+		Graph   struct {
+			Nodes []struct {
+				ID string `xml:"id,attr"`
+			} `xml:"nodes>node"`
+			Edges []struct {
+				Label string `xml:"label,attr"`
+			} `xml:"edges>edge"`
+		} `xml:"graph"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("GEXF is not well-formed: %v\n%s", err, out)
+	}
+	if doc.Version != "1.3" || len(doc.Graph.Nodes) != 4 || len(doc.Graph.Edges) != 4 {
+		t.Fatalf("unexpected GEXF shape: version=%s nodes=%d edges=%d", doc.Version, len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if !strings.Contains(out, `<attvalue for="testOnly" value="true"/>`) {
+		t.Errorf("expected a test-only node in GEXF output:\n%s", out)
+	}
+}
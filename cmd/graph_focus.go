@@ -0,0 +1,268 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+)
+
+// focusOptions controls the subgraph built around a single module.
+type focusOptions struct {
+	// Descendants also includes everything reachable from the focused module.
+	Descendants bool
+	// MaxDepth drops modules whose shortest depth from the main modules is
+	// greater than MaxDepth. A negative value means no limit.
+	MaxDepth int
+}
+
+// reverseGraph returns the graph with every edge flipped.
+func reverseGraph(graph map[string][]string) map[string][]string {
+	reverse := make(map[string][]string, len(graph))
+	for from, tos := range graph {
+		for _, to := range tos {
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+	for _, froms := range reverse {
+		sort.Strings(froms)
+	}
+	return reverse
+}
+
+// reachableFrom returns every module reachable from start, including start.
+func reachableFrom(start []string, graph map[string][]string) map[string]bool {
+	seen := make(map[string]bool, len(start))
+	queue := make([]string, 0, len(start))
+	for _, s := range start {
+		if !seen[s] {
+			seen[s] = true
+			queue = append(queue, s)
+		}
+	}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, next := range graph[current] {
+			if !seen[next] {
+				seen[next] = true
+				queue = append(queue, next)
+			}
+		}
+	}
+	return seen
+}
+
+// focusSubgraph returns the edges of the subgraph around dep: every edge
+// between modules that are reachable from a main module and can reach dep,
+// plus with opts.Descendants every edge among the modules reachable from dep.
+// Unlike enumerating all chains this is linear in the size of the graph.
+// Edges are sorted by source module, keeping the graph's neighbour order.
+func focusSubgraph(overview *DependencyOverview, dep string, opts focusOptions) ([]graphEdge, error) {
+	depth := shortestDepthByModule(overview.MainModules, overview.Graph)
+	if _, ok := depth[dep]; !ok {
+		return nil, fmt.Errorf("%s is not reachable from the main modules", dep)
+	}
+	if opts.MaxDepth >= 0 && depth[dep] > opts.MaxDepth {
+		return nil, fmt.Errorf("%s is at depth %d, beyond --max-depth %d", dep, depth[dep], opts.MaxDepth)
+	}
+
+	ancestors := reachableFrom([]string{dep}, reverseGraph(overview.Graph))
+	core := map[string]bool{}
+	for m := range ancestors {
+		if _, ok := depth[m]; ok {
+			core[m] = true
+		}
+	}
+	descendants := map[string]bool{}
+	if opts.Descendants {
+		descendants = reachableFrom([]string{dep}, overview.Graph)
+	}
+
+	keep := func(m string) bool {
+		if !core[m] && !descendants[m] {
+			return false
+		}
+		return opts.MaxDepth < 0 || depth[m] <= opts.MaxDepth
+	}
+
+	sources := make([]string, 0, len(core)+len(descendants))
+	for m := range core {
+		sources = append(sources, m)
+	}
+	for m := range descendants {
+		if !core[m] {
+			sources = append(sources, m)
+		}
+	}
+	sort.Strings(sources)
+
+	var edges []graphEdge
+	for _, from := range sources {
+		if !keep(from) {
+			continue
+		}
+		for _, to := range overview.Graph[from] {
+			if !keep(to) {
+				continue
+			}
+			if (core[from] && core[to]) || (descendants[from] && descendants[to]) {
+				edges = append(edges, graphEdge{From: from, To: to})
+			}
+		}
+	}
+	return edges, nil
+}
+
+// focusChainLimit bounds the number of chains focusChains records before
+// giving up, since a dense subgraph has exponentially many of them.
+const focusChainLimit = 10000
+
+// focusChains returns every maximal simple chain of edges starting from the
+// main modules, in the order of the edges. A chain also ends where it would
+// revisit one of its modules. ok is false if there are more than limit
+// chains.
+func focusChains(mainModules []string, edges []graphEdge, limit int) (chains []Chain, ok bool) {
+	graph := map[string][]string{}
+	for _, e := range edges {
+		graph[e.From] = append(graph[e.From], e.To)
+	}
+	var walk func(current string, chain Chain) bool
+	walk = func(current string, chain Chain) bool {
+		chain = append(chain, current)
+		if _, ok := graph[current]; !ok {
+			chains = append(chains, chain)
+			return len(chains) <= limit
+		}
+		for _, next := range graph[current] {
+			if contains(chain, next) {
+				chains = append(chains, chain)
+			} else if !walk(next, append(Chain{}, chain...)) {
+				return false
+			}
+			if len(chains) > limit {
+				return false
+			}
+		}
+		return true
+	}
+	for _, m := range mainModules {
+		if !walk(m, nil) {
+			return nil, false
+		}
+	}
+	return chains, true
+}
+
+// getFileContentsForFocus returns the .dot file contents for the subgraph
+// around dep, highlighting dep itself.
+func getFileContentsForFocus(edges []graphEdge, dep string) string {
+	name := func(m string) string {
+		if m == dep {
+			return "MainNode"
+		}
+		return "\"" + m + "\""
+	}
+	data := colorMainNode(dep)
+	for _, e := range edges {
+		data += fmt.Sprintf("%s -> %s\n", name(e.From), name(e.To))
+	}
+	return data
+}
+
+// reverseSubgraph returns the edges among dep and every module that depends
+// on it, directly or transitively, up to the main modules. With maxDepth >= 0
+// only dependents at most maxDepth edges above dep are kept.
+func reverseSubgraph(overview *DependencyOverview, dep string, maxDepth int) ([]graphEdge, error) {
+	reverse := reverseGraph(overview.Graph)
+	if _, ok := reverse[dep]; !ok && len(overview.Graph[dep]) == 0 {
+		return nil, fmt.Errorf("%s not found in the dependency graph", dep)
+	}
+	depth := shortestDepthByModule([]string{dep}, reverse)
+	keep := func(m string) bool {
+		d, ok := depth[m]
+		return ok && (maxDepth < 0 || d <= maxDepth)
+	}
+
+	sources := make([]string, 0, len(depth))
+	for m := range depth {
+		sources = append(sources, m)
+	}
+	sort.Strings(sources)
+
+	var edges []graphEdge
+	for _, from := range sources {
+		if !keep(from) {
+			continue
+		}
+		for _, to := range overview.Graph[from] {
+			if keep(to) {
+				edges = append(edges, graphEdge{From: from, To: to})
+			}
+		}
+	}
+	return edges, nil
+}
+
+// limitGraphDepth keeps only the modules whose shortest depth from the main
+// modules lies within [minDepth, maxDepth], and the edges between them. A
+// negative maxDepth means no upper bound. Node attributes such as degree and
+// depth are left as computed on the full graph.
+func limitGraphDepth(overview *DependencyOverview, nodes []graphNode, edges []graphEdge, minDepth, maxDepth int) (*DependencyOverview, []graphNode, []graphEdge) {
+	keep := map[string]bool{}
+	keptNodes := []graphNode{}
+	for _, n := range nodes {
+		if n.Depth < 0 || n.Depth < minDepth || (maxDepth >= 0 && n.Depth > maxDepth) {
+			continue
+		}
+		keep[n.Module] = true
+		keptNodes = append(keptNodes, n)
+	}
+
+	keptEdges := []graphEdge{}
+	graph := map[string][]string{}
+	for _, e := range edges {
+		if keep[e.From] && keep[e.To] {
+			keptEdges = append(keptEdges, e)
+		}
+	}
+	for from, tos := range overview.Graph {
+		if !keep[from] {
+			continue
+		}
+		for _, to := range tos {
+			if keep[to] {
+				graph[from] = append(graph[from], to)
+			}
+		}
+	}
+
+	filter := func(list []string) []string {
+		out := []string{}
+		for _, m := range list {
+			if keep[m] {
+				out = append(out, m)
+			}
+		}
+		return out
+	}
+	limited := *overview
+	limited.Graph = graph
+	limited.DirectDepList = filter(overview.DirectDepList)
+	limited.TransDepList = filter(overview.TransDepList)
+	return &limited, keptNodes, keptEdges
+}
@@ -0,0 +1,323 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// htmlGraphData is embedded into the HTML explorer. Nodes and edges are the
+// same values reported by "graph --json", plus version and test-only status.
+type htmlGraphData struct {
+	MainModules []string     `json:"mainModules"`
+	Nodes       []exportNode `json:"nodes"`
+	Edges       []exportEdge `json:"edges"`
+}
+
+// renderGraphHTML returns a single offline HTML page that explores the graph.
+func renderGraphHTML(mainModules []string, nodes []exportNode, edges []exportEdge) (string, error) {
+	// json.Marshal escapes <, > and & so the payload cannot close the
+	// surrounding <script> element.
+	data, err := json.Marshal(htmlGraphData{
+		MainModules: mainModules,
+		Nodes:       nodes,
+		Edges:       edges,
+	})
+	if err != nil {
+		return "", err
+	}
+	return strings.Replace(graphHTMLTemplate, "__DEPSTAT_DATA__", string(data), 1), nil
+}
+
+const graphHTMLTemplate = `<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>depstat graph explorer</title>
+<style>
+  * { box-sizing: border-box; }
+  body { margin: 0; font: 13px system-ui, -apple-system, sans-serif; color: #333; display: flex; height: 100vh; }
+  #left, #right { width: 300px; padding: 12px; overflow: auto; border-right: 1px solid #ddd; background: #fafafa; }
+  #right { border-right: none; border-left: 1px solid #ddd; width: 340px; }
+  #main { flex: 1; position: relative; overflow: hidden; }
+  #canvas { width: 100%; height: 100%; cursor: grab; }
+  h1 { font-size: 15px; margin: 0 0 8px; }
+  h2 { font-size: 13px; margin: 14px 0 6px; }
+  input[type=search] { width: 100%; padding: 6px; }
+  ul { list-style: none; margin: 0; padding: 0; }
+  li a { display: block; padding: 2px 4px; color: #0d47a1; text-decoration: none; word-break: break-all; cursor: pointer; }
+  li a:hover { background: #e3f2fd; }
+  button { margin: 2px 2px 2px 0; padding: 4px 8px; }
+  table { border-collapse: collapse; width: 100%; }
+  td { padding: 2px 4px; border-bottom: 1px solid #eee; vertical-align: top; word-break: break-all; }
+  td:first-child { color: #777; width: 90px; }
+  .muted { color: #999; }
+  .node rect { stroke-width: 1.5; }
+  .node text { font-size: 11px; pointer-events: none; }
+  .node { cursor: pointer; }
+  .node.main rect { fill: #e8f5e9; stroke: #388e3c; }
+  .node.dep rect { fill: #fff3e0; stroke: #f57c00; }
+  .node.test rect { fill: #f3e5f5; stroke: #8e24aa; stroke-dasharray: 4 2; }
+  .node.path rect { stroke: #d32f2f; stroke-width: 2.5; }
+  .node.selected rect { fill: #ffe0e0; stroke: #d32f2f; stroke-width: 3; }
+  .edge { fill: none; stroke: #aaa; stroke-width: 1.2; }
+  .edge.direct { stroke: #1976d2; }
+  .edge.path { stroke: #d32f2f; stroke-width: 2.5; }
+</style>
+</head>
+<body>
+<div id="left">
+  <h1>depstat graph explorer</h1>
+  <div id="summary" class="muted"></div>
+  <h2>Search</h2>
+  <input id="search" type="search" placeholder="module path..." autocomplete="off">
+  <ul id="results"></ul>
+  <h2>View</h2>
+  <label><input id="hideTest" type="checkbox"> Hide test-only modules</label><br>
+  <button id="reset">Reset view</button>
+  <button id="fit">Fit</button>
+  <p class="muted">Click a module to select it. Drag to pan, scroll to zoom.</p>
+</div>
+<div id="main"><svg id="canvas" xmlns="http://www.w3.org/2000/svg">
+  <defs><marker id="arrow" viewBox="0 0 10 6" refX="10" refY="3" markerWidth="8" markerHeight="5" orient="auto"><path d="M0 0L10 3L0 6z" fill="#888"/></marker></defs>
+  <g id="viewport"></g>
+</svg></div>
+<div id="right"><div id="details" class="muted">No module selected.</div></div>
+<script type="application/json" id="depstat-data">__DEPSTAT_DATA__</script>
+<script>
+(function () {
+  "use strict";
+  var data = JSON.parse(document.getElementById("depstat-data").textContent);
+  var nodes = data.nodes, index = new Map();
+  nodes.forEach(function (n, i) { index.set(n.module, i); });
+  var out = nodes.map(function () { return []; });
+  var inc = nodes.map(function () { return []; });
+  var edgeType = new Map();
+  data.edges.forEach(function (e) {
+    var a = index.get(e.from), b = index.get(e.to);
+    out[a].push(b); inc[b].push(a); edgeType.set(a + ">" + b, e.type);
+  });
+  var mains = data.mainModules.map(function (m) { return index.get(m); }).filter(function (i) { return i !== undefined; });
+
+  var visible, selected, pathNodes, pathEdges, hideTest = false;
+  var view = { x: 0, y: 0, w: 1000, h: 800 };
+  var svg = document.getElementById("canvas"), vp = document.getElementById("viewport");
+  var NS = "http://www.w3.org/2000/svg";
+
+  function reset() {
+    visible = new Set(mains);
+    mains.forEach(function (m) { out[m].forEach(function (d) { visible.add(d); }); });
+    selected = null; pathNodes = new Set(); pathEdges = new Set();
+  }
+
+  function shown(i) { return visible.has(i) && (!hideTest || !nodes[i].testOnly || i === selected); }
+
+  // whyPaths marks every node and edge that lies on a shortest path from a
+  // main module to target, using the BFS depth computed by depstat.
+  function whyPaths(target) {
+    pathNodes = new Set(); pathEdges = new Set();
+    if (nodes[target].depth < 0) { return; }
+    var queue = [target], seen = new Set([target]);
+    while (queue.length) {
+      var v = queue.shift();
+      pathNodes.add(v); visible.add(v);
+      inc[v].forEach(function (u) {
+        if (nodes[u].depth >= 0 && nodes[u].depth + 1 === nodes[v].depth) {
+          pathEdges.add(u + ">" + v);
+          if (!seen.has(u)) { seen.add(u); queue.push(u); }
+        }
+      });
+    }
+  }
+
+  function select(i) {
+    selected = i; visible.add(i); whyPaths(i); render(); details(); center(i);
+  }
+
+  function el(tag, attrs, parent) {
+    var e = document.createElementNS(NS, tag);
+    Object.keys(attrs).forEach(function (k) { e.setAttribute(k, attrs[k]); });
+    if (parent) { parent.appendChild(e); }
+    return e;
+  }
+
+  var pos = new Map();
+  function layout() {
+    pos = new Map();
+    var layers = new Map(), maxDepth = 0;
+    nodes.forEach(function (n) { if (n.depth > maxDepth) { maxDepth = n.depth; } });
+    visible.forEach(function (i) {
+      if (!shown(i)) { return; }
+      var d = nodes[i].depth < 0 ? maxDepth + 1 : nodes[i].depth;
+      if (!layers.has(d)) { layers.set(d, []); }
+      layers.get(d).push(i);
+    });
+    var keys = Array.from(layers.keys()).sort(function (a, b) { return a - b; });
+    var widest = 0;
+    keys.forEach(function (k, row) {
+      var layer = layers.get(k);
+      layer.sort(function (a, b) { return nodes[a].module < nodes[b].module ? -1 : 1; });
+      var x = 0;
+      layer.forEach(function (i) {
+        var w = Math.max(140, nodes[i].module.length * 6.6 + 20);
+        pos.set(i, { x: x, y: row * 90, w: w, h: 30 });
+        x += w + 20;
+      });
+      if (x > widest) { widest = x; }
+      layer.widthUsed = x;
+    });
+    keys.forEach(function (k) {
+      var layer = layers.get(k), shift = (widest - layer.widthUsed) / 2;
+      layer.forEach(function (i) { pos.get(i).x += shift; });
+    });
+  }
+
+  function render() {
+    layout();
+    while (vp.firstChild) { vp.removeChild(vp.firstChild); }
+    pos.forEach(function (p, a) {
+      out[a].forEach(function (b) {
+        var q = pos.get(b);
+        if (!q) { return; }
+        var key = a + ">" + b, cls = "edge";
+        if (edgeType.get(key) === "direct") { cls += " direct"; }
+        if (pathEdges.has(key)) { cls += " path"; }
+        var x1 = p.x + p.w / 2, y1 = p.y + p.h, x2 = q.x + q.w / 2, y2 = q.y;
+        if (y2 <= p.y) { y1 = p.y; y2 = q.y + q.h; }
+        el("path", { "class": cls, d: "M" + x1 + " " + y1 + "Q" + (x1 + x2) / 2 + " " + (y1 + y2) / 2 + " " + x2 + " " + y2, "marker-end": "url(#arrow)" }, vp);
+      });
+    });
+    pos.forEach(function (p, i) {
+      var n = nodes[i], cls = "node " + (n.isMainModule ? "main" : (n.testOnly ? "test" : "dep"));
+      if (pathNodes.has(i)) { cls += " path"; }
+      if (i === selected) { cls += " selected"; }
+      var g = el("g", { "class": cls, transform: "translate(" + p.x + "," + p.y + ")" }, vp);
+      el("title", {}, g).textContent = n.module + (n.version ? "@" + n.version : "");
+      el("rect", { width: p.w, height: p.h, rx: 5 }, g);
+      var t = el("text", { x: p.w / 2, y: p.h / 2 + 4, "text-anchor": "middle" }, g);
+      t.textContent = n.module;
+      g.addEventListener("click", function (ev) { ev.stopPropagation(); select(i); });
+    });
+    document.getElementById("summary").textContent =
+      nodes.length + " modules, " + data.edges.length + " edges; showing " + pos.size + ".";
+    applyView();
+  }
+
+  function applyView() { svg.setAttribute("viewBox", view.x + " " + view.y + " " + view.w + " " + view.h); }
+
+  function fit() {
+    var minX = Infinity, minY = Infinity, maxX = -Infinity, maxY = -Infinity;
+    pos.forEach(function (p) {
+      minX = Math.min(minX, p.x); minY = Math.min(minY, p.y);
+      maxX = Math.max(maxX, p.x + p.w); maxY = Math.max(maxY, p.y + p.h);
+    });
+    if (minX === Infinity) { return; }
+    var r = svg.getBoundingClientRect(), pad = 40;
+    var w = maxX - minX + 2 * pad, h = maxY - minY + 2 * pad, aspect = r.width / Math.max(1, r.height);
+    if (w / h < aspect) { w = h * aspect; } else { h = w / aspect; }
+    view = { x: (minX + maxX) / 2 - w / 2, y: (minY + maxY) / 2 - h / 2, w: w, h: h };
+    applyView();
+  }
+
+  function center(i) {
+    var p = pos.get(i);
+    if (!p) { return; }
+    view.x = p.x + p.w / 2 - view.w / 2; view.y = p.y + p.h / 2 - view.h / 2;
+    applyView();
+  }
+
+  function link(i, parent) {
+    var li = document.createElement("li"), a = document.createElement("a");
+    a.textContent = nodes[i].module + (nodes[i].testOnly ? " (test-only)" : "");
+    a.addEventListener("click", function () { select(i); });
+    li.appendChild(a); parent.appendChild(li);
+  }
+
+  function button(label, fn, parent) {
+    var b = document.createElement("button");
+    b.textContent = label; b.addEventListener("click", fn); parent.appendChild(b);
+  }
+
+  function details() {
+    var box = document.getElementById("details");
+    box.className = ""; box.innerHTML = "";
+    if (selected === null) { box.className = "muted"; box.textContent = "No module selected."; return; }
+    var n = nodes[selected];
+    var h = document.createElement("h1"); h.textContent = n.module; box.appendChild(h);
+    var table = document.createElement("table");
+    [["version", n.version || "(main module)"], ["depth", n.depth < 0 ? "unreachable" : n.depth],
+     ["in-degree", n.inDegree], ["out-degree", n.outDegree], ["dependents", n.dependents],
+     ["betweenness", n.betweenness.toFixed(4)], ["PageRank", n.pageRank.toFixed(4)], ["main module", n.isMainModule],
+     ["test-only", n.testOnly]].forEach(function (row) {
+      var tr = document.createElement("tr");
+      row.forEach(function (c) { var td = document.createElement("td"); td.textContent = c; tr.appendChild(td); });
+      table.appendChild(tr);
+    });
+    box.appendChild(table);
+    var actions = document.createElement("div");
+    button("Expand dependencies", function () { out[selected].forEach(function (d) { visible.add(d); }); render(); }, actions);
+    button("Expand dependents", function () { inc[selected].forEach(function (d) { visible.add(d); }); render(); }, actions);
+    button("Hide", function () { visible.delete(selected); selected = null; pathNodes = new Set(); pathEdges = new Set(); render(); details(); }, actions);
+    box.appendChild(actions);
+    var sections = [["Why (shortest paths)", Array.from(pathNodes).filter(function (i) { return i !== selected; })],
+                    ["Dependencies", out[selected]], ["Dependents", inc[selected]]];
+    sections.forEach(function (s) {
+      var h2 = document.createElement("h2"); h2.textContent = s[0] + " (" + s[1].length + ")"; box.appendChild(h2);
+      var ul = document.createElement("ul");
+      s[1].slice().sort(function (a, b) { return nodes[a].module < nodes[b].module ? -1 : 1; }).forEach(function (i) { link(i, ul); });
+      box.appendChild(ul);
+    });
+  }
+
+  document.getElementById("search").addEventListener("input", function (ev) {
+    var q = ev.target.value.trim().toLowerCase(), ul = document.getElementById("results");
+    ul.innerHTML = "";
+    if (!q) { return; }
+    var hits = [];
+    for (var i = 0; i < nodes.length && hits.length < 50; i++) {
+      if (nodes[i].module.toLowerCase().indexOf(q) >= 0 && (!hideTest || !nodes[i].testOnly)) { hits.push(i); }
+    }
+    hits.forEach(function (i) { link(i, ul); });
+  });
+  document.getElementById("hideTest").addEventListener("change", function (ev) { hideTest = ev.target.checked; render(); });
+  document.getElementById("reset").addEventListener("click", function () { reset(); render(); details(); fit(); });
+  document.getElementById("fit").addEventListener("click", fit);
+
+  var drag = null;
+  svg.addEventListener("mousedown", function (ev) { drag = { x: ev.clientX, y: ev.clientY, vx: view.x, vy: view.y }; });
+  window.addEventListener("mouseup", function () { drag = null; });
+  window.addEventListener("mousemove", function (ev) {
+    if (!drag) { return; }
+    var r = svg.getBoundingClientRect(), s = view.w / r.width;
+    view.x = drag.vx - (ev.clientX - drag.x) * s; view.y = drag.vy - (ev.clientY - drag.y) * s;
+    applyView();
+  });
+  svg.addEventListener("wheel", function (ev) {
+    ev.preventDefault();
+    var r = svg.getBoundingClientRect(), f = ev.deltaY > 0 ? 1.15 : 1 / 1.15;
+    var mx = view.x + (ev.clientX - r.left) / r.width * view.w, my = view.y + (ev.clientY - r.top) / r.height * view.h;
+    view.w *= f; view.h *= f; view.x = mx - (mx - view.x) * f; view.y = my - (my - view.y) * f;
+    applyView();
+  }, { passive: false });
+
+  reset(); render(); fit();
+})();
+</script>
+</body>
+</html>
+`
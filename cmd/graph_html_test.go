@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func Test_renderGraphHTML(t *testing.T) {
+	nodes, edges := exportTestGraph()
+	nodes[0].Module = "</script><b>main"
+	edges[0].From = nodes[0].Module
+	page, err := renderGraphHTML([]string{nodes[0].Module}, nodes, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	again, _ := renderGraphHTML([]string{nodes[0].Module}, nodes, edges)
+	if page != again {
+		t.Fatal("HTML output is not deterministic")
+	}
+	if strings.Contains(page, "__DEPSTAT_DATA__") {
+		t.Fatal("data placeholder was not replaced")
+	}
+	if strings.Count(page, "</script>") != 2 {
+		t.Fatalf("module names must not terminate the data script, got %d </script> tags", strings.Count(page, "</script>"))
+	}
+	for _, ref := range []string{"src=", "href=\"http", "@import"} {
+		if strings.Contains(page, ref) {
+			t.Errorf("page must be self-contained, found %q", ref)
+		}
+	}
+
+	start := strings.Index(page, `id="depstat-data">`) + len(`id="depstat-data">`)
+	end := strings.Index(page[start:], "</script>")
+	var data htmlGraphData
+	if err := json.Unmarshal([]byte(page[start:start+end]), &data); err != nil {
+		t.Fatalf("embedded data is not valid JSON: %v", err)
+	}
+	if len(data.Nodes) != len(nodes) || len(data.Edges) != len(edges) {
+		t.Fatalf("embedded graph size mismatch: %d nodes, %d edges", len(data.Nodes), len(data.Edges))
+	}
+	for i, n := range data.Nodes {
+		if n != nodes[i] {
+			t.Errorf("node %d: got %+v, want %+v", i, n, nodes[i])
+		}
+	}
+	if data.MainModules[0] != "</script><b>main" {
+		t.Errorf("main module not preserved: %q", data.MainModules[0])
+	}
+}
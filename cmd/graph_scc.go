@@ -0,0 +1,256 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import "sort"
+
+// stronglyConnectedComponents returns the strongly connected components of
+// graph using Tarjan's algorithm. Members of each component are sorted, and
+// components are returned in reverse topological order: every component
+// comes after all components it depends on.
+func stronglyConnectedComponents(graph map[string][]string) [][]string {
+	nodeSet := map[string]bool{}
+	for from, tos := range graph {
+		nodeSet[from] = true
+		for _, to := range tos {
+			nodeSet[to] = true
+		}
+	}
+	nodes := make([]string, 0, len(nodeSet))
+	for n := range nodeSet {
+		nodes = append(nodes, n)
+	}
+	sort.Strings(nodes)
+
+	index := map[string]int{}
+	lowlink := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	var components [][]string
+
+	var strongConnect func(v string)
+	strongConnect = func(v string) {
+		index[v] = len(index)
+		lowlink[v] = index[v]
+		stack = append(stack, v)
+		onStack[v] = true
+
+		for _, w := range graph[v] {
+			if _, visited := index[w]; !visited {
+				strongConnect(w)
+				if lowlink[w] < lowlink[v] {
+					lowlink[v] = lowlink[w]
+				}
+			} else if onStack[w] && index[w] < lowlink[v] {
+				lowlink[v] = index[w]
+			}
+		}
+
+		if lowlink[v] == index[v] {
+			var component []string
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			sort.Strings(component)
+			components = append(components, component)
+		}
+	}
+
+	for _, n := range nodes {
+		if _, visited := index[n]; !visited {
+			strongConnect(n)
+		}
+	}
+	return components
+}
+
+// condenseGraph replaces every strongly connected component with more than
+// one module by a single super-node named after its first module, so the
+// resulting graph is a DAG. Main modules in a component are merged like any
+// other module and listed in the group; the super-node becomes a main module.
+func condenseGraph(overview *DependencyOverview) (*DependencyOverview, []collapsedGroup) {
+	mainSet := map[string]bool{}
+	for _, m := range overview.MainModules {
+		mainSet[m] = true
+	}
+	rep := map[string]string{}
+	groups := []collapsedGroup{}
+	for _, component := range stronglyConnectedComponents(overview.Graph) {
+		if len(component) < 2 {
+			continue
+		}
+		name := "scc:" + component[0]
+		group := collapsedGroup{Node: name, MemberCount: len(component), Members: component}
+		for _, m := range component {
+			rep[m] = name
+			if mainSet[m] {
+				group.MainModules = append(group.MainModules, m)
+			}
+		}
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Node < groups[j].Node })
+	return mergeModules(overview, rep), groups
+}
+
+// transitiveReduction returns a copy of overview without the edges implied
+// by longer paths. Cycles make the reduction ambiguous, so it is computed on
+// the condensation: edges inside a strongly connected component are kept, and
+// an edge between two components is dropped when the target component is
+// reachable through another component.
+func transitiveReduction(overview *DependencyOverview) *DependencyOverview {
+	components := stronglyConnectedComponents(overview.Graph)
+	componentOf := map[string]string{}
+	for _, component := range components {
+		for _, m := range component {
+			componentOf[m] = component[0]
+		}
+	}
+
+	condensed := map[string][]string{}
+	var edges [][2]string
+	seen := map[[2]string]bool{}
+	for _, from := range sortedGraphKeys(overview.Graph) {
+		for _, to := range overview.Graph[from] {
+			e := [2]string{componentOf[from], componentOf[to]}
+			if e[0] == e[1] || seen[e] {
+				continue
+			}
+			seen[e] = true
+			condensed[e[0]] = append(condensed[e[0]], e[1])
+			edges = append(edges, e)
+		}
+	}
+	kept := map[[2]string]bool{}
+	for _, e := range reduceEdges(edges, condensed, nil) {
+		kept[e] = true
+	}
+
+	reduced := *overview
+	reduced.Graph = map[string][]string{}
+	for from, tos := range overview.Graph {
+		for _, to := range tos {
+			f, t := componentOf[from], componentOf[to]
+			if f == t || kept[[2]string{f, t}] {
+				reduced.Graph[from] = append(reduced.Graph[from], to)
+			}
+		}
+	}
+	return &reduced
+}
+
+// sortedGraphKeys returns the modules with outgoing edges in graph, in
+// order.
+func sortedGraphKeys(graph map[string][]string) []string {
+	keys := make([]string, 0, len(graph))
+	for k := range graph {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// reduceEdges returns the edges that are not implied by a longer path in
+// graph, in their original order. When marked is not nil, a longer path only
+// implies an edge if at least one of its edges is marked.
+func reduceEdges(edges [][2]string, graph map[string][]string, marked map[[2]string]bool) [][2]string {
+	// Number the modules so that the search for every edge runs on slices.
+	index := map[string]int{}
+	var names []string
+	id := func(m string) int {
+		i, ok := index[m]
+		if !ok {
+			i = len(names)
+			index[m] = i
+			names = append(names, m)
+		}
+		return i
+	}
+	for _, from := range sortedGraphKeys(graph) {
+		id(from)
+		for _, to := range graph[from] {
+			id(to)
+		}
+	}
+	for _, e := range edges {
+		id(e[0])
+		id(e[1])
+	}
+	type arc struct {
+		to     int
+		marked bool
+	}
+	adjacent := make([][]arc, len(names))
+	for from, tos := range graph {
+		for _, to := range tos {
+			adjacent[index[from]] = append(adjacent[index[from]], arc{index[to], marked == nil || marked[[2]string{from, to}]})
+		}
+	}
+
+	// A search state is a module and whether a marked edge was used to reach
+	// it, numbered 2*module+used. seen holds the search that last reached
+	// each state.
+	seen := make([]int, 2*len(names))
+	var queue []int
+	reachable := func(search, src, dst int) bool {
+		queue = queue[:0]
+		visit := func(state int) {
+			if seen[state] != search {
+				seen[state] = search
+				queue = append(queue, state)
+			}
+		}
+		for _, a := range adjacent[src] {
+			if a.to != dst {
+				visit(2*a.to + boolToInt(a.marked))
+			}
+		}
+		for len(queue) > 0 {
+			state := queue[0]
+			queue = queue[1:]
+			node, used := state/2, state%2 == 1
+			if node == dst && used {
+				return true
+			}
+			for _, a := range adjacent[node] {
+				visit(2*a.to + boolToInt(used || a.marked))
+			}
+		}
+		return false
+	}
+
+	var reduced [][2]string
+	for i, e := range edges {
+		if !reachable(i+1, index[e[0]], index[e[1]]) {
+			reduced = append(reduced, e)
+		}
+	}
+	return reduced
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_stronglyConnectedComponents(t *testing.T) {
+	graph := map[string][]string{
+		"main": {"A", "D"},
+		"A":    {"B"},
+		"B":    {"C"},
+		"C":    {"A", "D"},
+		"D":    {"E"},
+		"E":    {"D"},
+	}
+	components := stronglyConnectedComponents(graph)
+	want := [][]string{{"D", "E"}, {"A", "B", "C"}, {"main"}}
+	if !reflect.DeepEqual(components, want) {
+		t.Errorf("stronglyConnectedComponents = %v, want %v", components, want)
+	}
+}
+
+func Test_condenseGraph(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules:   []string{"main"},
+		DirectDepList: []string{"A", "D"},
+		TransDepList:  []string{"B", "C", "E"},
+		Graph: map[string][]string{
+			"main": {"A", "D"},
+			"A":    {"B"},
+			"B":    {"C"},
+			"C":    {"A", "D"},
+			"D":    {"E"},
+			"E":    {"D"},
+		},
+		Versions: map[string]string{"A": "v1.0.0"},
+	}
+	condensed, groups := condenseGraph(overview)
+	wantGraph := map[string][]string{
+		"main":  {"scc:A", "scc:D"},
+		"scc:A": {"scc:D"},
+	}
+	if !reflect.DeepEqual(condensed.Graph, wantGraph) {
+		t.Errorf("condensed graph = %v, want %v", condensed.Graph, wantGraph)
+	}
+	wantGroups := []collapsedGroup{
+		{Node: "scc:A", MemberCount: 3, Members: []string{"A", "B", "C"}},
+		{Node: "scc:D", MemberCount: 2, Members: []string{"D", "E"}},
+	}
+	if !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("groups = %+v, want %+v", groups, wantGroups)
+	}
+	requireAcyclic(t, condensed.Graph)
+}
+
+// requireAcyclic fails unless every strongly connected component of graph is
+// a single module without a self-loop.
+func requireAcyclic(t *testing.T, graph map[string][]string) {
+	t.Helper()
+	for _, component := range stronglyConnectedComponents(graph) {
+		if len(component) > 1 || contains(graph[component[0]], component[0]) {
+			t.Errorf("condensed graph %v still has the cycle %v", graph, component)
+		}
+	}
+}
+
+func Test_condenseGraph_mainModules(t *testing.T) {
+	// A and B are only in a cycle through main; they are not linked to each
+	// other, but the whole component has to be merged to leave a DAG.
+	overview := &DependencyOverview{
+		MainModules:   []string{"main"},
+		DirectDepList: []string{"A", "B"},
+		TransDepList:  []string{"C"},
+		Graph: map[string][]string{
+			"main": {"A", "B"},
+			"A":    {"main"},
+			"B":    {"main", "C"},
+		},
+	}
+	condensed, groups := condenseGraph(overview)
+	if want := map[string][]string{"scc:A": {"C"}}; !reflect.DeepEqual(condensed.Graph, want) {
+		t.Errorf("condensed graph = %v, want %v", condensed.Graph, want)
+	}
+	requireAcyclic(t, condensed.Graph)
+	wantGroups := []collapsedGroup{{Node: "scc:A", MemberCount: 3, Members: []string{"A", "B", "main"}, MainModules: []string{"main"}}}
+	if !reflect.DeepEqual(groups, wantGroups) {
+		t.Errorf("groups = %+v, want %+v", groups, wantGroups)
+	}
+	if !reflect.DeepEqual(condensed.MainModules, []string{"scc:A"}) {
+		t.Errorf("main modules = %v, want [scc:A]", condensed.MainModules)
+	}
+	if !reflect.DeepEqual(condensed.DirectDepList, []string{}) || !reflect.DeepEqual(condensed.TransDepList, []string{"C"}) {
+		t.Errorf("dependency lists = %v, %v", condensed.DirectDepList, condensed.TransDepList)
+	}
+}
+
+func Test_condenseGraph_severalMainModules(t *testing.T) {
+	// main and staging are in the same cycle and both stay listed.
+	overview := &DependencyOverview{
+		MainModules: []string{"main", "staging", "other"},
+		Graph: map[string][]string{
+			"main":    {"staging", "A"},
+			"staging": {"A"},
+			"A":       {"B"},
+			"B":       {"A", "main", "C"},
+			"other":   {"C"},
+		},
+	}
+	condensed, groups := condenseGraph(overview)
+	want := map[string][]string{"scc:A": {"C"}, "other": {"C"}}
+	if !reflect.DeepEqual(condensed.Graph, want) {
+		t.Errorf("condensed graph = %v, want %v", condensed.Graph, want)
+	}
+	requireAcyclic(t, condensed.Graph)
+	if len(groups) != 1 || !reflect.DeepEqual(groups[0].MainModules, []string{"main", "staging"}) {
+		t.Errorf("groups = %+v", groups)
+	}
+	if !reflect.DeepEqual(condensed.MainModules, []string{"scc:A", "other"}) {
+		t.Errorf("main modules = %v, want [scc:A other]", condensed.MainModules)
+	}
+}
+
+func Test_transitiveReduction(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules: []string{"main"},
+		Graph: map[string][]string{
+			"main": {"A", "B", "C"},
+			"A":    {"B", "C"},
+			"B":    {"C"},
+		},
+	}
+	reduced := transitiveReduction(overview)
+	want := map[string][]string{"main": {"A"}, "A": {"B"}, "B": {"C"}}
+	if !reflect.DeepEqual(reduced.Graph, want) {
+		t.Errorf("reduced graph = %v, want %v", reduced.Graph, want)
+	}
+	if len(overview.Graph["main"]) != 3 {
+		t.Error("the original overview must not be modified")
+	}
+}
+
+func Test_transitiveReduction_cycles(t *testing.T) {
+	// Edges inside the A <-> B cycle are kept; main -> C is implied by
+	// main -> A -> B -> C.
+	overview := &DependencyOverview{
+		MainModules: []string{"main"},
+		Graph: map[string][]string{
+			"main": {"A", "C"},
+			"A":    {"B"},
+			"B":    {"A", "C"},
+		},
+	}
+	reduced := transitiveReduction(overview)
+	want := map[string][]string{"main": {"A"}, "A": {"B"}, "B": {"A", "C"}}
+	if !reflect.DeepEqual(reduced.Graph, want) {
+		t.Errorf("reduced graph = %v, want %v", reduced.Graph, want)
+	}
+}
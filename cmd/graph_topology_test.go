@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -169,3 +171,238 @@ func Test_buildGraphTopology_unreachableDepth(t *testing.T) {
 		}
 	}
 }
+
+func Test_focusSubgraph(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules: []string{"main1", "main2"},
+		Graph: map[string][]string{
+			"main1": {"A", "B"},
+			"main2": {"C"},
+			"A":     {"X"},
+			"B":     {"D"},
+			"C":     {"X"},
+			"X":     {"Y"},
+			"Y":     {"Z"},
+		},
+	}
+	edgeSet := func(edges []graphEdge) map[string]bool {
+		set := map[string]bool{}
+		for _, e := range edges {
+			set[e.From+" -> "+e.To] = true
+		}
+		return set
+	}
+
+	edges, err := focusSubgraph(overview, "X", focusOptions{Descendants: true, MaxDepth: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := edgeSet(edges)
+	for _, want := range []string{"main1 -> A", "A -> X", "main2 -> C", "C -> X", "X -> Y", "Y -> Z"} {
+		if !got[want] {
+			t.Errorf("missing edge %s in %v", want, got)
+		}
+	}
+	if got["main1 -> B"] || got["B -> D"] || len(got) != 6 {
+		t.Errorf("unexpected edges: %v", got)
+	}
+
+	edges, _ = focusSubgraph(overview, "X", focusOptions{MaxDepth: -1})
+	if got := edgeSet(edges); got["X -> Y"] || len(got) != 4 {
+		t.Errorf("descendants should be excluded: %v", got)
+	}
+
+	edges, _ = focusSubgraph(overview, "X", focusOptions{Descendants: true, MaxDepth: 3})
+	if got := edgeSet(edges); !got["X -> Y"] || got["Y -> Z"] {
+		t.Errorf("--max-depth 3 should keep X -> Y and drop Y -> Z: %v", got)
+	}
+
+	if _, err := focusSubgraph(overview, "Y", focusOptions{MaxDepth: 2}); err == nil {
+		t.Error("expected an error for a dependency beyond --max-depth")
+	}
+	if _, err := focusSubgraph(overview, "missing", focusOptions{MaxDepth: -1}); err == nil {
+		t.Error("expected an error for an unreachable dependency")
+	}
+}
+
+func Test_focusChains_coverFocusSubgraphOnDAG(t *testing.T) {
+	graph := map[string][]string{
+		"main": {"A", "B", "C"},
+		"A":    {"D", "E"},
+		"B":    {"E"},
+		"C":    {"F"},
+		"D":    {"G"},
+		"E":    {"G", "H"},
+		"F":    {"H"},
+	}
+	overview := &DependencyOverview{MainModules: []string{"main"}, Graph: graph}
+
+	// On a DAG every edge of the focus subgraph lies on a chain through dep,
+	// so the chain output draws exactly the focus subgraph.
+	for _, dep := range []string{"A", "E", "G", "H"} {
+		edges, err := focusSubgraph(overview, dep, focusOptions{Descendants: true, MaxDepth: -1})
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := map[string]bool{}
+		for _, e := range edges {
+			want[e.From+" -> "+e.To] = true
+		}
+		chains, ok := focusChains(overview.MainModules, edges, focusChainLimit)
+		if !ok {
+			t.Fatalf("%s: too many chains", dep)
+		}
+		got := map[string]bool{}
+		for _, chain := range chains {
+			if !contains(chain, dep) {
+				t.Errorf("%s: chain %v does not go through it", dep, chain)
+			}
+			for i := 1; i < len(chain); i++ {
+				got[chain[i-1]+" -> "+chain[i]] = true
+			}
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: chains cover %v, focus subgraph is %v", dep, got, want)
+		}
+	}
+}
+
+func Test_focusChains_limit(t *testing.T) {
+	// 20 layers of two fully connected modules have 2^20 chains.
+	var edges []graphEdge
+	prev := []string{"main"}
+	for i := 0; i < 20; i++ {
+		next := []string{fmt.Sprintf("L%da", i), fmt.Sprintf("L%db", i)}
+		for _, from := range prev {
+			for _, to := range next {
+				edges = append(edges, graphEdge{From: from, To: to})
+			}
+		}
+		prev = next
+	}
+	if _, ok := focusChains([]string{"main"}, edges, focusChainLimit); ok {
+		t.Error("expected focusChains to give up beyond the limit")
+	}
+}
+
+func Test_focusSubgraph_largeGraph(t *testing.T) {
+	// 60 layers of two fully connected modules have 2^60 paths; the focus
+	// subgraph must not enumerate them.
+	graph := map[string][]string{"main": {"L0a", "L0b"}}
+	for i := 0; i < 59; i++ {
+		next := []string{fmt.Sprintf("L%da", i+1), fmt.Sprintf("L%db", i+1)}
+		graph[fmt.Sprintf("L%da", i)] = next
+		graph[fmt.Sprintf("L%db", i)] = next
+	}
+	overview := &DependencyOverview{MainModules: []string{"main"}, Graph: graph}
+	edges, err := focusSubgraph(overview, "L30a", focusOptions{Descendants: true, MaxDepth: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(edges) != 2+29*4+2+28*4+2 {
+		t.Errorf("unexpected edge count %d", len(edges))
+	}
+}
+
+func Test_reverseSubgraph(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules: []string{"main"},
+		Graph: map[string][]string{
+			"main": {"A", "B"},
+			"A":    {"C"},
+			"B":    {"D"},
+			"C":    {"X"},
+			"D":    {"X", "E"},
+			"X":    {"Y"},
+		},
+	}
+	edges, err := reverseSubgraph(overview, "X", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []graphEdge{
+		{From: "A", To: "C"}, {From: "B", To: "D"}, {From: "C", To: "X"},
+		{From: "D", To: "X"}, {From: "main", To: "A"}, {From: "main", To: "B"},
+	}
+	if !reflect.DeepEqual(edges, want) {
+		t.Errorf("reverseSubgraph = %v, want %v", edges, want)
+	}
+
+	edges, _ = reverseSubgraph(overview, "X", 1)
+	if want := []graphEdge{{From: "C", To: "X"}, {From: "D", To: "X"}}; !reflect.DeepEqual(edges, want) {
+		t.Errorf("reverseSubgraph with max depth 1 = %v, want %v", edges, want)
+	}
+
+	if _, err := reverseSubgraph(overview, "missing", -1); err == nil {
+		t.Error("expected an error for an unknown module")
+	}
+}
+
+func Test_limitGraphDepth(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules:   []string{"main"},
+		DirectDepList: []string{"A", "B"},
+		TransDepList:  []string{"C", "D"},
+		Graph: map[string][]string{
+			"main": {"A", "B"},
+			"A":    {"C"},
+			"B":    {"C"},
+			"C":    {"D"},
+		},
+	}
+	nodes, edges := buildGraphTopology(overview)
+
+	limited, keptNodes, keptEdges := limitGraphDepth(overview, nodes, edges, 1, 2)
+	modules := []string{}
+	for _, n := range keptNodes {
+		modules = append(modules, n.Module)
+	}
+	if !reflect.DeepEqual(modules, []string{"A", "B", "C"}) {
+		t.Errorf("kept nodes = %v", modules)
+	}
+	if len(keptEdges) != 2 || len(limited.Graph["main"]) != 0 || len(limited.Graph["C"]) != 0 {
+		t.Errorf("unexpected edges %v, graph %v", keptEdges, limited.Graph)
+	}
+	if !reflect.DeepEqual(limited.TransDepList, []string{"C"}) {
+		t.Errorf("TransDepList = %v, want [C]", limited.TransDepList)
+	}
+	if len(overview.Graph["main"]) != 2 {
+		t.Error("the original overview must not be modified")
+	}
+	for _, n := range keptNodes {
+		if n.Module == "C" && n.InDegree != 2 {
+			t.Errorf("node attributes should come from the full graph: %+v", n)
+		}
+	}
+}
+
+func Test_limitGraphDepth_dot(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules:   []string{"main"},
+		DirectDepList: []string{"A", "B"},
+		TransDepList:  []string{"C"},
+		Graph: map[string][]string{
+			"main": {"A", "B"},
+			"A":    {"C"},
+			"B":    {"C"},
+		},
+	}
+	nodes, edges := buildGraphTopology(overview)
+
+	// --min-depth 1 removes the main module, so there is no MainNode.
+	limited, _, _ := limitGraphDepth(overview, nodes, edges, 1, -1)
+	want := `"A" -> "C"
+"B" -> "C"
+`
+	if got := getFileContentsForAllDeps(limited); got != want {
+		t.Errorf("DOT for --min-depth 1 = %q, want %q", got, want)
+	}
+
+	// --max-depth 0 keeps only the main module.
+	limited, _, _ = limitGraphDepth(overview, nodes, edges, 0, 0)
+	want = `MainNode [label="main", style="filled" color="yellow"]
+`
+	if got := getFileContentsForAllDeps(limited); got != want {
+		t.Errorf("DOT for --max-depth 0 = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,236 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var layersMainOnly bool
+
+// dependencyLayer is a set of modules whose dependencies all live in lower
+// layers. Modules in a cycle always share a layer and are also listed as a
+// group in Cycles.
+type dependencyLayer struct {
+	Layer   int        `json:"layer"`
+	Modules []string   `json:"modules"`
+	Cycles  [][]string `json:"cycles,omitempty"`
+}
+
+var layersCmd = &cobra.Command{
+	Use:   "layers",
+	Short: "Group modules into topological layers (build order)",
+	Long: `Sorts the module graph topologically and prints it layer by layer. Layer 0
+	holds the leaves (modules without dependencies); every other layer holds
+	modules whose dependencies are all in lower layers, so the layers give a
+	bottom-up order for coordinated bumps.
+
+	Cycles are collapsed first (as in graph --condense): all modules of a cycle
+	share a layer and are reported as a group.
+
+	Use --main-modules-only together with --mainModules to layer only the main
+	modules, e.g. the Kubernetes staging repositories. A main module then
+	depends on another one if it can reach it through non-main modules.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if len(args) != 0 {
+			return fmt.Errorf("layers does not take any arguments")
+		}
+		if jsonOutput && csvOutput {
+			return fmt.Errorf("--json and --csv are mutually exclusive")
+		}
+		overview := getDepInfo(mainModules)
+		if len(overview.MainModules) == 0 {
+			return fmt.Errorf("no main modules remain after exclusions; adjust --exclude-modules or --mainModules")
+		}
+
+		graph := overview.Graph
+		if layersMainOnly {
+			graph = mainModuleGraph(overview.MainModules, overview.Graph)
+		}
+		layers := computeLayers(graph, overview.MainModules)
+
+		switch {
+		case jsonOutput:
+			outputObj := struct {
+				MainModulesOnly bool              `json:"mainModulesOnly"`
+				LayerCount      int               `json:"layerCount"`
+				Layers          []dependencyLayer `json:"layers"`
+			}{
+				MainModulesOnly: layersMainOnly,
+				LayerCount:      len(layers),
+				Layers:          layers,
+			}
+			out, err := json.MarshalIndent(outputObj, "", "\t")
+			if err != nil {
+				return err
+			}
+			fmt.Print(string(out))
+		case csvOutput:
+			fmt.Print(layersCSV(layers))
+		default:
+			printLayers(layers)
+		}
+		return nil
+	},
+}
+
+// mainModuleGraph projects graph onto the main modules: there is an edge
+// from one main module to another when the latter can be reached through
+// non-main modules only.
+func mainModuleGraph(mainModules []string, graph map[string][]string) map[string][]string {
+	mainSet := map[string]bool{}
+	for _, m := range mainModules {
+		mainSet[m] = true
+	}
+	projected := map[string][]string{}
+	for _, m := range mainModules {
+		projected[m] = []string{}
+		seen := map[string]bool{m: true}
+		queue := []string{m}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range graph[current] {
+				if seen[next] {
+					continue
+				}
+				seen[next] = true
+				if mainSet[next] {
+					projected[m] = append(projected[m], next)
+					continue
+				}
+				queue = append(queue, next)
+			}
+		}
+		sort.Strings(projected[m])
+	}
+	return projected
+}
+
+// computeLayers assigns each strongly connected component of graph to the
+// layer one above its highest dependency. Main modules are included even if
+// they have no edges.
+func computeLayers(graph map[string][]string, mainModules []string) []dependencyLayer {
+	withMain := make(map[string][]string, len(graph)+len(mainModules))
+	for from, tos := range graph {
+		withMain[from] = tos
+	}
+	for _, m := range mainModules {
+		if _, ok := withMain[m]; !ok {
+			withMain[m] = nil
+		}
+	}
+	components := stronglyConnectedComponents(withMain)
+	componentOf := map[string]int{}
+	for i, component := range components {
+		for _, m := range component {
+			componentOf[m] = i
+		}
+	}
+
+	// Components come in reverse topological order, so every dependency's
+	// layer is known by the time a component is visited.
+	layerOf := make([]int, len(components))
+	byLayer := map[int][]int{}
+	maxLayer := -1
+	for i, component := range components {
+		for _, m := range component {
+			for _, to := range withMain[m] {
+				if c := componentOf[to]; c != i && layerOf[c]+1 > layerOf[i] {
+					layerOf[i] = layerOf[c] + 1
+				}
+			}
+		}
+		byLayer[layerOf[i]] = append(byLayer[layerOf[i]], i)
+		if layerOf[i] > maxLayer {
+			maxLayer = layerOf[i]
+		}
+	}
+
+	layers := make([]dependencyLayer, 0, maxLayer+1)
+	for l := 0; l <= maxLayer; l++ {
+		layer := dependencyLayer{Layer: l, Modules: []string{}}
+		for _, c := range byLayer[l] {
+			layer.Modules = append(layer.Modules, components[c]...)
+			if len(components[c]) > 1 {
+				layer.Cycles = append(layer.Cycles, components[c])
+			}
+		}
+		sort.Strings(layer.Modules)
+		sort.Slice(layer.Cycles, func(i, j int) bool { return layer.Cycles[i][0] < layer.Cycles[j][0] })
+		layers = append(layers, layer)
+	}
+	return layers
+}
+
+func printLayers(layers []dependencyLayer) {
+	for _, layer := range layers {
+		cycleOf := map[string]int{}
+		for i, cycle := range layer.Cycles {
+			for _, m := range cycle {
+				cycleOf[m] = i
+			}
+		}
+		fmt.Printf("Layer %d (%d modules):\n", layer.Layer, len(layer.Modules))
+		printed := map[int]bool{}
+		for _, m := range layer.Modules {
+			i, inCycle := cycleOf[m]
+			if !inCycle {
+				fmt.Printf("  %s\n", m)
+				continue
+			}
+			if !printed[i] {
+				printed[i] = true
+				fmt.Printf("  [cycle] %s\n", strings.Join(layer.Cycles[i], ", "))
+			}
+		}
+	}
+}
+
+// layersCSV renders one row per module. Modules in a cycle share a Cycle
+// value naming the first module of the cycle.
+func layersCSV(layers []dependencyLayer) string {
+	var b strings.Builder
+	b.WriteString("Layer,Module,Cycle\n")
+	for _, layer := range layers {
+		cycleName := map[string]string{}
+		for _, cycle := range layer.Cycles {
+			for _, m := range cycle {
+				cycleName[m] = "scc:" + cycle[0]
+			}
+		}
+		for _, m := range layer.Modules {
+			fmt.Fprintf(&b, "%d,%s,%s\n", layer.Layer, m, cycleName[m])
+		}
+	}
+	return b.String()
+}
+
+func init() {
+	rootCmd.AddCommand(layersCmd)
+	layersCmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory containing the module to evaluate. Defaults to the current directory.")
+	layersCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Get the output in JSON format")
+	layersCmd.Flags().BoolVarP(&csvOutput, "csv", "c", false, "Get the output in CSV format")
+	layersCmd.Flags().BoolVar(&layersMainOnly, "main-modules-only", false, "Only layer the main modules, following dependencies between them")
+	layersCmd.Flags().StringSliceVar(&excludeModules, "exclude-modules", []string{}, "Exclude module path patterns (repeatable, supports * wildcard)")
+	layersCmd.Flags().StringSliceVarP(&mainModules, "mainModules", "m", []string{}, "Specify main modules")
+}
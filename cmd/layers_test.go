@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_computeLayers(t *testing.T) {
+	graph := map[string][]string{
+		"main": {"A", "B"},
+		"A":    {"C"},
+		"B":    {"D"},
+		"D":    {"E"},
+		"E":    {"D", "C"},
+	}
+	layers := computeLayers(graph, []string{"main"})
+	want := []dependencyLayer{
+		{Layer: 0, Modules: []string{"C"}},
+		{Layer: 1, Modules: []string{"A", "D", "E"}, Cycles: [][]string{{"D", "E"}}},
+		{Layer: 2, Modules: []string{"B"}},
+		{Layer: 3, Modules: []string{"main"}},
+	}
+	if !reflect.DeepEqual(layers, want) {
+		t.Errorf("computeLayers = %+v, want %+v", layers, want)
+	}
+
+	csv := layersCSV(layers)
+	wantCSV := "Layer,Module,Cycle\n0,C,\n1,A,\n1,D,scc:D\n1,E,scc:D\n2,B,\n3,main,\n"
+	if csv != wantCSV {
+		t.Errorf("layersCSV = %q, want %q", csv, wantCSV)
+	}
+}
+
+func Test_computeLayers_mainModulesOnly(t *testing.T) {
+	// k8s.io/kubernetes -> client-go -> (lib) -> apimachinery; api has no
+	// main-module dependencies and the lonely main module has no edges.
+	graph := map[string][]string{
+		"k8s.io/kubernetes":   {"k8s.io/client-go", "k8s.io/api"},
+		"k8s.io/client-go":    {"example.com/lib"},
+		"example.com/lib":     {"k8s.io/apimachinery"},
+		"k8s.io/api":          {"k8s.io/apimachinery"},
+		"k8s.io/apimachinery": {"example.com/util"},
+	}
+	mains := []string{"k8s.io/kubernetes", "k8s.io/client-go", "k8s.io/api", "k8s.io/apimachinery", "k8s.io/lonely"}
+	projected := mainModuleGraph(mains, graph)
+	if !reflect.DeepEqual(projected["k8s.io/client-go"], []string{"k8s.io/apimachinery"}) {
+		t.Errorf("client-go should depend on apimachinery through example.com/lib: %v", projected)
+	}
+
+	layers := computeLayers(projected, mains)
+	want := []dependencyLayer{
+		{Layer: 0, Modules: []string{"k8s.io/apimachinery", "k8s.io/lonely"}},
+		{Layer: 1, Modules: []string{"k8s.io/api", "k8s.io/client-go"}},
+		{Layer: 2, Modules: []string{"k8s.io/kubernetes"}},
+	}
+	if !reflect.DeepEqual(layers, want) {
+		t.Errorf("computeLayers = %+v, want %+v", layers, want)
+	}
+}
@@ -26,6 +26,13 @@ import (
 
 var listSplitTestOnly bool
 var listJSONOutput bool
+var listAttribution bool
+
+// depAttribution records which main modules pull in a dependency.
+type depAttribution struct {
+	MainModule string `json:"mainModule"`
+	Direct     bool   `json:"direct"`
+}
 
 // analyzeDepsCmd represents the analyzeDeps command
 var listCmd = &cobra.Command{
@@ -46,6 +53,13 @@ var listCmd = &cobra.Command{
 		allDeps := getAllDeps(depGraph.DirectDepList, depGraph.TransDepList)
 		sort.Strings(allDeps)
 
+		var attribution map[string][]depAttribution
+		var attributionMap, directAttributionMap map[string][]string
+		if listAttribution {
+			attribution = computeAttribution(depGraph)
+			attributionMap, directAttributionMap = attributionMaps(attribution)
+		}
+
 		if listSplitTestOnly {
 			testOnlySet, err := classifyTestDeps(allDeps)
 			if err != nil {
@@ -57,13 +71,15 @@ var listCmd = &cobra.Command{
 			sort.Strings(testOnly)
 			if listJSONOutput {
 				outputObj := struct {
-					All       []string `json:"allDependencies"`
-					NonTest   []string `json:"nonTestDependencies"`
-					TestOnly  []string `json:"testOnlyDependencies"`
-					MainMods  []string `json:"mainModules"`
-					Total     int      `json:"totalDependencies"`
-					NonTestN  int      `json:"nonTestCount"`
-					TestOnlyN int      `json:"testOnlyCount"`
+					All       []string            `json:"allDependencies"`
+					NonTest   []string            `json:"nonTestDependencies"`
+					TestOnly  []string            `json:"testOnlyDependencies"`
+					MainMods  []string            `json:"mainModules"`
+					Total     int                 `json:"totalDependencies"`
+					NonTestN  int                 `json:"nonTestCount"`
+					TestOnlyN int                 `json:"testOnlyCount"`
+					Attr      map[string][]string `json:"attribution,omitempty"`
+					DirectBy  map[string][]string `json:"directAttribution,omitempty"`
 				}{
 					All:       allDeps,
 					NonTest:   nonTest,
@@ -72,6 +88,8 @@ var listCmd = &cobra.Command{
 					Total:     len(allDeps),
 					NonTestN:  len(nonTest),
 					TestOnlyN: len(testOnly),
+					Attr:      attributionMap,
+					DirectBy:  directAttributionMap,
 				}
 				outputRaw, err := json.MarshalIndent(outputObj, "", "\t")
 				if err != nil {
@@ -81,19 +99,23 @@ var listCmd = &cobra.Command{
 				return nil
 			}
 			fmt.Printf("Non-test dependencies (%d):\n", len(nonTest))
-			printDeps(nonTest)
+			printDepsWithAttribution(nonTest, attribution)
 			fmt.Printf("\nTest-only dependencies (%d):\n", len(testOnly))
-			printDeps(testOnly)
+			printDepsWithAttribution(testOnly, attribution)
 		} else {
 			if listJSONOutput {
 				outputObj := struct {
-					All      []string `json:"allDependencies"`
-					MainMods []string `json:"mainModules"`
-					Total    int      `json:"totalDependencies"`
+					All      []string            `json:"allDependencies"`
+					MainMods []string            `json:"mainModules"`
+					Total    int                 `json:"totalDependencies"`
+					Attr     map[string][]string `json:"attribution,omitempty"`
+					DirectBy map[string][]string `json:"directAttribution,omitempty"`
 				}{
 					All:      allDeps,
 					MainMods: depGraph.MainModules,
 					Total:    len(allDeps),
+					Attr:     attributionMap,
+					DirectBy: directAttributionMap,
 				}
 				outputRaw, err := json.MarshalIndent(outputObj, "", "\t")
 				if err != nil {
@@ -103,17 +125,94 @@ var listCmd = &cobra.Command{
 				return nil
 			}
 			fmt.Println("List of all dependencies:")
-			printDeps(allDeps)
+			printDepsWithAttribution(allDeps, attribution)
 		}
 		return nil
 	},
 }
 
+// computeAttribution returns, for every dependency, the main modules whose
+// reachable subgraph contains it and whether each of them requires it directly.
+func computeAttribution(overview *DependencyOverview) map[string][]depAttribution {
+	attribution := map[string][]depAttribution{}
+	for _, mainMod := range overview.MainModules {
+		direct := map[string]bool{}
+		for _, dep := range overview.Graph[mainMod] {
+			direct[dep] = true
+		}
+		reachable := map[string]bool{mainMod: true}
+		queue := []string{mainMod}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, next := range overview.Graph[current] {
+				if reachable[next] {
+					continue
+				}
+				reachable[next] = true
+				queue = append(queue, next)
+			}
+		}
+		for dep := range reachable {
+			if dep == mainMod {
+				continue
+			}
+			attribution[dep] = append(attribution[dep], depAttribution{MainModule: mainMod, Direct: direct[dep]})
+		}
+	}
+	for dep := range attribution {
+		sort.Slice(attribution[dep], func(i, j int) bool {
+			return attribution[dep][i].MainModule < attribution[dep][j].MainModule
+		})
+	}
+	return attribution
+}
+
+// attributionMaps flattens attribution into module -> main modules maps,
+// one for every requiring main module and one for direct requirers only.
+func attributionMaps(attribution map[string][]depAttribution) (map[string][]string, map[string][]string) {
+	all := make(map[string][]string, len(attribution))
+	direct := map[string][]string{}
+	for dep, entries := range attribution {
+		all[dep] = []string{}
+		for _, e := range entries {
+			all[dep] = append(all[dep], e.MainModule)
+			if e.Direct {
+				direct[dep] = append(direct[dep], e.MainModule)
+			}
+		}
+	}
+	return all, direct
+}
+
+// printDepsWithAttribution prints deps like printDeps and, when attribution
+// is set, lists the main modules that pull in each dependency.
+func printDepsWithAttribution(deps []string, attribution map[string][]depAttribution) {
+	if attribution == nil {
+		printDeps(deps)
+		return
+	}
+	fmt.Println()
+	sort.Strings(deps)
+	for _, dep := range deps {
+		fmt.Println(dep)
+		for _, e := range attribution[dep] {
+			if e.Direct {
+				fmt.Printf("  <- %s (direct)\n", e.MainModule)
+			} else {
+				fmt.Printf("  <- %s\n", e.MainModule)
+			}
+		}
+	}
+	fmt.Println()
+}
+
 func init() {
 	rootCmd.AddCommand(listCmd)
 	listCmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory containing the module to evaluate. Defaults to the current directory.")
 	listCmd.Flags().StringSliceVarP(&mainModules, "mainModules", "m", []string{}, "Specify main modules")
 	listCmd.Flags().StringSliceVar(&excludeModules, "exclude-modules", []string{}, "Exclude module path patterns (repeatable, supports * wildcard)")
 	listCmd.Flags().BoolVarP(&listJSONOutput, "json", "j", false, "Get the output in JSON format")
+	listCmd.Flags().BoolVar(&listAttribution, "attribution", false, "Show which main modules pull in each dependency and whether they require it directly")
 	listCmd.Flags().BoolVar(&listSplitTestOnly, "split-test-only", false, "Split list into test-only and non-test sections (uses go mod why -m)")
 }
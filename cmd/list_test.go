@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_computeAttribution(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules: []string{"root", "staging"},
+		Graph: map[string][]string{
+			"root":    {"staging", "A"},
+			"staging": {"B"},
+			"A":       {"C"},
+			"B":       {"C"},
+		},
+	}
+	attribution := computeAttribution(overview)
+
+	want := map[string][]depAttribution{
+		"staging": {{MainModule: "root", Direct: true}},
+		"A":       {{MainModule: "root", Direct: true}},
+		"B":       {{MainModule: "root", Direct: false}, {MainModule: "staging", Direct: true}},
+		"C":       {{MainModule: "root", Direct: false}, {MainModule: "staging", Direct: false}},
+	}
+	if !reflect.DeepEqual(attribution, want) {
+		t.Fatalf("computeAttribution() = %v, want %v", attribution, want)
+	}
+
+	all, direct := attributionMaps(attribution)
+	if !reflect.DeepEqual(all["C"], []string{"root", "staging"}) {
+		t.Errorf("attribution[C] = %v, want [root staging]", all["C"])
+	}
+	if _, ok := direct["C"]; ok {
+		t.Errorf("C should have no direct requirers, got %v", direct["C"])
+	}
+	if !reflect.DeepEqual(direct["B"], []string{"staging"}) {
+		t.Errorf("directAttribution[B] = %v, want [staging]", direct["B"])
+	}
+}
@@ -0,0 +1,189 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Mermaid edge styles.
+const (
+	mermaidSolid  = "solid"
+	mermaidThick  = "thick"
+	mermaidDotted = "dotted"
+)
+
+// mermaidClassDefs are the node classes shared by all Mermaid outputs. The
+// colours mirror the ones used in the DOT output of each command.
+var mermaidClassDefs = map[string]string{
+	"main":      "fill:#ffff99,stroke:#333",
+	"target":    "fill:#ffffcc,stroke:#d32f2f,stroke-width:2px",
+	"mainmod":   "fill:#ccffcc,stroke:#388e3c",
+	"added":     "fill:#ccffcc,stroke:#2e7d32",
+	"removed":   "fill:#ffcccc,stroke:#c62828,stroke-dasharray:5 3",
+	"changed":   "fill:#ffffcc,stroke:#f9a825",
+	"unchanged": "fill:#ffffff,stroke:#999",
+	"context":   "fill:#e8e8e8,stroke:#999",
+	"mutual":    "fill:#ffcdd2,stroke:#c62828",
+}
+
+type mermaidEdge struct {
+	From, To string
+	Style    string // one of mermaidSolid, mermaidThick, mermaidDotted
+	Color    string // optional stroke colour
+	Label    string // optional edge label
+}
+
+// mermaidSubgraph groups nodes in a titled box.
+type mermaidSubgraph struct {
+	Title string
+	Nodes []string
+}
+
+// mermaidGraph accumulates nodes and edges and renders them as a Mermaid
+// flowchart. Node IDs are generated, since module paths are not valid IDs.
+type mermaidGraph struct {
+	Title     string
+	Direction string
+	labels    map[string]string
+	classes   map[string]string
+	nodes     []string
+	edges     []mermaidEdge
+	edgeSeen  map[mermaidEdge]bool
+	subgraphs []mermaidSubgraph
+}
+
+func newMermaidGraph(title, direction string) *mermaidGraph {
+	return &mermaidGraph{
+		Title:     title,
+		Direction: direction,
+		labels:    map[string]string{},
+		classes:   map[string]string{},
+		edgeSeen:  map[mermaidEdge]bool{},
+	}
+}
+
+// AddNode registers node with an optional label and class. Re-adding a node
+// updates its label and class if they are non-empty.
+func (g *mermaidGraph) AddNode(node, label, class string) {
+	if _, ok := g.labels[node]; !ok {
+		g.nodes = append(g.nodes, node)
+		g.labels[node] = node
+	}
+	if label != "" {
+		g.labels[node] = label
+	}
+	if class != "" {
+		g.classes[node] = class
+	}
+}
+
+// AddEdge adds an edge, registering both endpoints as plain nodes if needed.
+// Duplicate edges are ignored, matching the "strict" DOT graphs.
+func (g *mermaidGraph) AddEdge(e mermaidEdge) {
+	g.AddNode(e.From, "", "")
+	g.AddNode(e.To, "", "")
+	if g.edgeSeen[e] {
+		return
+	}
+	g.edgeSeen[e] = true
+	g.edges = append(g.edges, e)
+}
+
+// AddSubgraph draws nodes, which must already be registered, in a box.
+func (g *mermaidGraph) AddSubgraph(title string, nodes []string) {
+	g.subgraphs = append(g.subgraphs, mermaidSubgraph{Title: title, Nodes: nodes})
+}
+
+func (g *mermaidGraph) String() string {
+	var b strings.Builder
+	if g.Title != "" {
+		fmt.Fprintf(&b, "---\ntitle: %q\n---\n", g.Title)
+	}
+	direction := g.Direction
+	if direction == "" {
+		direction = "TD"
+	}
+	fmt.Fprintf(&b, "flowchart %s\n", direction)
+
+	ids := make(map[string]string, len(g.nodes))
+	for i, node := range g.nodes {
+		ids[node] = fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&b, "    %s[\"%s\"]\n", ids[node], mermaidEscape(g.labels[node]))
+	}
+	for i, sg := range g.subgraphs {
+		fmt.Fprintf(&b, "    subgraph s%d[\"%s\"]\n", i, mermaidEscape(sg.Title))
+		for _, node := range sg.Nodes {
+			fmt.Fprintf(&b, "        %s\n", ids[node])
+		}
+		fmt.Fprintln(&b, "    end")
+	}
+
+	linksByColor := map[string][]string{}
+	for i, e := range g.edges {
+		arrow := "-->"
+		switch e.Style {
+		case mermaidThick:
+			arrow = "==>"
+		case mermaidDotted:
+			arrow = "-.->"
+		}
+		if e.Label != "" {
+			arrow += "|" + mermaidEscape(e.Label) + "|"
+		}
+		fmt.Fprintf(&b, "    %s %s %s\n", ids[e.From], arrow, ids[e.To])
+		if e.Color != "" {
+			linksByColor[e.Color] = append(linksByColor[e.Color], fmt.Sprint(i))
+		}
+	}
+
+	byClass := map[string][]string{}
+	for _, node := range g.nodes {
+		if class := g.classes[node]; class != "" {
+			byClass[class] = append(byClass[class], ids[node])
+		}
+	}
+	classNames := make([]string, 0, len(byClass))
+	for class := range byClass {
+		classNames = append(classNames, class)
+	}
+	sort.Strings(classNames)
+	for _, class := range classNames {
+		fmt.Fprintf(&b, "    classDef %s %s\n", class, mermaidClassDefs[class])
+		fmt.Fprintf(&b, "    class %s %s\n", strings.Join(byClass[class], ","), class)
+	}
+	colors := make([]string, 0, len(linksByColor))
+	for color := range linksByColor {
+		colors = append(colors, color)
+	}
+	sort.Strings(colors)
+	for _, color := range colors {
+		fmt.Fprintf(&b, "    linkStyle %s stroke:%s\n", strings.Join(linksByColor[color], ","), color)
+	}
+	return b.String()
+}
+
+// mermaidEscape makes s safe inside a quoted Mermaid label.
+func mermaidEscape(s string) string {
+	s = strings.ReplaceAll(s, "\"", "#quot;")
+	s = strings.ReplaceAll(s, "<", "#lt;")
+	s = strings.ReplaceAll(s, ">", "#gt;")
+	s = strings.ReplaceAll(s, "\n", "<br/>")
+	return s
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestMermaidGraphString(t *testing.T) {
+	g := newMermaidGraph("Why: a\"b", "LR")
+	g.AddNode("A", "", "main")
+	g.AddEdge(mermaidEdge{From: "A", To: "B<x>", Style: mermaidThick, Color: "blue"})
+	g.AddEdge(mermaidEdge{From: "A", To: "B<x>", Style: mermaidThick, Color: "blue"})
+	g.AddEdge(mermaidEdge{From: "B<x>", To: "C", Style: mermaidDotted, Color: "gray"})
+
+	want := `---
+title: "Why: a\"b"
+---
+flowchart LR
+    n0["A"]
+    n1["B#lt;x#gt;"]
+    n2["C"]
+    n0 ==> n1
+    n1 -.-> n2
+    classDef main fill:#ffff99,stroke:#333
+    class n0 main
+    linkStyle 0 stroke:blue
+    linkStyle 1 stroke:gray
+`
+	if got := g.String(); got != want {
+		t.Fatalf("unexpected mermaid output:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestGetMermaidForAllDepsWithTypes(t *testing.T) {
+	overview := &DependencyOverview{
+		MainModules:   []string{"A"},
+		DirectDepList: []string{"B"},
+		TransDepList:  []string{"C"},
+		Graph: map[string][]string{
+			"A": {"B"},
+			"B": {"C"},
+		},
+	}
+	out := getMermaidForAllDepsWithTypes(overview, true)
+	for _, want := range []string{"n0 ==> n1", "n1 -.-> n2", "class n0 main", "linkStyle 0 stroke:blue", "linkStyle 1 stroke:gray"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
+
+func TestOutputDiffMermaidColours(t *testing.T) {
+	base := &DependencyOverview{
+		MainModules: []string{"main"},
+		Graph:       map[string][]string{"main": {"old", "C"}},
+	}
+	head := &DependencyOverview{
+		MainModules: []string{"main"},
+		Graph:       map[string][]string{"main": {"new", "C"}},
+	}
+	result := DiffResult{
+		BaseRef:        "base",
+		HeadRef:        "head",
+		Added:          []string{"new"},
+		Removed:        []string{"old"},
+		EdgesAdded:     []string{"main -> new"},
+		EdgesRemoved:   []string{"main -> old"},
+		VersionChanges: []VersionChange{{Path: "C", Before: "v1", After: "v2"}},
+	}
+
+	output := captureStdout(t, func() {
+		if err := outputDiffMermaid(result, base, head); err != nil {
+			t.Fatalf("outputDiffMermaid returned error: %v", err)
+		}
+	})
+	for _, want := range []string{
+		`title: "Dependency Diff: base..head"`,
+		"flowchart LR",
+		`["C<br/>v1 → v2"]`,
+		"classDef added ",
+		"classDef removed ",
+		"classDef changed ",
+		"stroke:green",
+		"stroke:red",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in output:\n%s", want, output)
+		}
+	}
+}
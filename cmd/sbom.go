@@ -0,0 +1,476 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+const (
+	sbomFormatCycloneDX = "cyclonedx-json"
+	sbomFormatSPDX      = "spdx-json"
+)
+
+var sbomFormat string
+var sbomOutputPath string
+var sbomSkipTestScope bool
+
+// cdxBOM is a CycloneDX 1.5 JSON document.
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `json:"timestamp"`
+	Tools     cdxTools      `json:"tools"`
+	Component *cdxComponent `json:"component,omitempty"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string         `json:"type"`
+	BOMRef     string         `json:"bom-ref,omitempty"`
+	Name       string         `json:"name"`
+	Version    string         `json:"version,omitempty"`
+	Scope      string         `json:"scope,omitempty"`
+	Purl       string         `json:"purl,omitempty"`
+	Components []cdxComponent `json:"components,omitempty"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn"`
+}
+
+// spdxDocument is an SPDX 2.3 JSON document.
+type spdxDocument struct {
+	SPDXVersion       string             `json:"spdxVersion"`
+	DataLicense       string             `json:"dataLicense"`
+	SPDXID            string             `json:"SPDXID"`
+	Name              string             `json:"name"`
+	DocumentNamespace string             `json:"documentNamespace"`
+	CreationInfo      spdxCreationInfo   `json:"creationInfo"`
+	Packages          []spdxPackage      `json:"packages"`
+	Relationships     []spdxRelationship `json:"relationships"`
+}
+
+type spdxCreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdxPackage struct {
+	Name             string            `json:"name"`
+	SPDXID           string            `json:"SPDXID"`
+	VersionInfo      string            `json:"versionInfo,omitempty"`
+	DownloadLocation string            `json:"downloadLocation"`
+	FilesAnalyzed    bool              `json:"filesAnalyzed"`
+	PrimaryPurpose   string            `json:"primaryPackagePurpose,omitempty"`
+	Comment          string            `json:"comment,omitempty"`
+	ExternalRefs     []spdxExternalRef `json:"externalRefs,omitempty"`
+}
+
+type spdxExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdxRelationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+// sbomInfo carries the document identity fields that differ between runs,
+// so that the document builders stay deterministic for a given input.
+type sbomInfo struct {
+	Timestamp time.Time
+	UUID      string
+	Tool      string
+	Version   string
+}
+
+var sbomCmd = &cobra.Command{
+	Use:   "sbom",
+	Short: "Export the dependency graph as a CycloneDX or SPDX SBOM",
+	Long: `Export a software bill of materials for the module graph.
+
+Main modules are emitted as the root components, every reachable dependency
+is emitted with its effective version and a pkg:golang package URL, and the
+dependency relationships mirror the edges reported by "depstat graph".
+
+Dependencies that are only reachable through test imports are marked with
+scope "excluded" (CycloneDX) or a TEST_DEPENDENCY_OF relationship (SPDX).
+Test-only classification uses "go mod why -m"; pass --skip-test-scope to
+disable it.
+
+Examples:
+  # CycloneDX JSON to stdout
+  depstat sbom --format cyclonedx-json
+
+  # SPDX JSON for Kubernetes, written to a file
+  depstat sbom --format spdx-json -m "$MAIN_MODULES" --output sbom.spdx.json`,
+	RunE: runSBOM,
+}
+
+func runSBOM(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("sbom does not take any arguments")
+	}
+	if sbomFormat != sbomFormatCycloneDX && sbomFormat != sbomFormatSPDX {
+		return fmt.Errorf("--format must be one of: %s, %s", sbomFormatCycloneDX, sbomFormatSPDX)
+	}
+
+	overview := getDepInfo(mainModules)
+	if len(overview.MainModules) == 0 {
+		return fmt.Errorf("no main modules remain after exclusions; adjust --exclude-modules or --mainModules")
+	}
+
+	testOnlySet := map[string]bool{}
+	if !sbomSkipTestScope {
+		allDeps := getAllDeps(overview.DirectDepList, overview.TransDepList)
+		sort.Strings(allDeps)
+		var err error
+		testOnlySet, err = classifyTestDeps(allDeps)
+		if err != nil {
+			return fmt.Errorf("failed to classify dependencies: %w", err)
+		}
+	}
+
+	uuid, err := newUUID()
+	if err != nil {
+		return fmt.Errorf("failed to generate document identifier: %w", err)
+	}
+	info := sbomInfo{
+		Timestamp: time.Now().UTC(),
+		UUID:      uuid,
+		Tool:      "depstat",
+		Version:   DepstatVersion,
+	}
+
+	var doc interface{}
+	if sbomFormat == sbomFormatCycloneDX {
+		doc = buildCycloneDX(overview, testOnlySet, info)
+	} else {
+		doc = buildSPDX(overview, testOnlySet, info)
+	}
+	out, err := json.MarshalIndent(doc, "", "\t")
+	if err != nil {
+		return err
+	}
+	out = append(out, '\n')
+
+	if sbomOutputPath == "" {
+		fmt.Print(string(out))
+		return nil
+	}
+	if err := os.WriteFile(sbomOutputPath, out, 0644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Created %s file!\n", sbomOutputPath)
+	return nil
+}
+
+// sbomModules returns main modules followed by all dependencies, sorted
+// within each group, which is the component order used in both formats.
+func sbomModules(overview *DependencyOverview) []string {
+	mainSet := map[string]bool{}
+	for _, m := range overview.MainModules {
+		mainSet[m] = true
+	}
+	var deps []string
+	for _, dep := range getAllDeps(overview.DirectDepList, overview.TransDepList) {
+		// "go" and "toolchain" requirements are not modules and have no purl
+		if dep == "go" || dep == "toolchain" {
+			continue
+		}
+		if !mainSet[dep] {
+			deps = append(deps, dep)
+		}
+	}
+	sort.Strings(deps)
+	return append(append([]string{}, overview.MainModules...), deps...)
+}
+
+// sbomEdges returns the sorted, de-duplicated adjacency list of every module
+// in modules, restricted to targets that are also in modules.
+func sbomEdges(overview *DependencyOverview, modules []string) map[string][]string {
+	known := map[string]bool{}
+	for _, m := range modules {
+		known[m] = true
+	}
+	edges := map[string][]string{}
+	for _, from := range modules {
+		seen := map[string]bool{}
+		for _, to := range overview.Graph[from] {
+			if !known[to] || seen[to] {
+				continue
+			}
+			seen[to] = true
+			edges[from] = append(edges[from], to)
+		}
+		sort.Strings(edges[from])
+	}
+	return edges
+}
+
+func buildCycloneDX(overview *DependencyOverview, testOnlySet map[string]bool, info sbomInfo) cdxBOM {
+	modules := sbomModules(overview)
+	mainSet := map[string]bool{}
+	for _, m := range overview.MainModules {
+		mainSet[m] = true
+	}
+
+	component := func(mod string) cdxComponent {
+		c := cdxComponent{
+			Type:    "library",
+			BOMRef:  goPurl(mod, overview.Versions[mod]),
+			Name:    mod,
+			Version: overview.Versions[mod],
+			Purl:    goPurl(mod, overview.Versions[mod]),
+		}
+		if mainSet[mod] {
+			c.Type = "application"
+		} else if testOnlySet[mod] {
+			c.Scope = "excluded"
+		} else {
+			c.Scope = "required"
+		}
+		return c
+	}
+
+	// The first main module describes the document; any further main
+	// modules are nested under it so that all of them are root components.
+	root := component(overview.MainModules[0])
+	for _, m := range overview.MainModules[1:] {
+		root.Components = append(root.Components, component(m))
+	}
+
+	components := []cdxComponent{}
+	for _, mod := range modules {
+		if !mainSet[mod] {
+			components = append(components, component(mod))
+		}
+	}
+
+	edges := sbomEdges(overview, modules)
+	dependencies := make([]cdxDependency, 0, len(modules))
+	for _, mod := range modules {
+		dependsOn := []string{}
+		for _, to := range edges[mod] {
+			dependsOn = append(dependsOn, goPurl(to, overview.Versions[to]))
+		}
+		dependencies = append(dependencies, cdxDependency{
+			Ref:       goPurl(mod, overview.Versions[mod]),
+			DependsOn: dependsOn,
+		})
+	}
+
+	tool := cdxComponent{Type: "application", Name: info.Tool, Version: info.Version}
+	return cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  "1.5",
+		SerialNumber: "urn:uuid:" + info.UUID,
+		Version:      1,
+		Metadata: cdxMetadata{
+			Timestamp: info.Timestamp.Format(time.RFC3339),
+			Tools:     cdxTools{Components: []cdxComponent{tool}},
+			Component: &root,
+		},
+		Components:   components,
+		Dependencies: dependencies,
+	}
+}
+
+func buildSPDX(overview *DependencyOverview, testOnlySet map[string]bool, info sbomInfo) spdxDocument {
+	modules := sbomModules(overview)
+	mainSet := map[string]bool{}
+	for _, m := range overview.MainModules {
+		mainSet[m] = true
+	}
+
+	ids := spdxIDs(modules)
+	packages := make([]spdxPackage, 0, len(modules))
+	for _, mod := range modules {
+		version := overview.Versions[mod]
+		pkg := spdxPackage{
+			Name:             mod,
+			SPDXID:           ids[mod],
+			VersionInfo:      version,
+			DownloadLocation: "NOASSERTION",
+			ExternalRefs: []spdxExternalRef{{
+				ReferenceCategory: "PACKAGE-MANAGER",
+				ReferenceType:     "purl",
+				ReferenceLocator:  goPurl(mod, version),
+			}},
+		}
+		if mainSet[mod] {
+			pkg.PrimaryPurpose = "APPLICATION"
+		} else {
+			pkg.PrimaryPurpose = "LIBRARY"
+		}
+		if testOnlySet[mod] {
+			pkg.Comment = "test-only dependency"
+		}
+		packages = append(packages, pkg)
+	}
+
+	relationships := []spdxRelationship{}
+	for _, m := range overview.MainModules {
+		relationships = append(relationships, spdxRelationship{
+			SPDXElementID:      "SPDXRef-DOCUMENT",
+			RelationshipType:   "DESCRIBES",
+			RelatedSPDXElement: ids[m],
+		})
+	}
+	edges := sbomEdges(overview, modules)
+	for _, from := range modules {
+		for _, to := range edges[from] {
+			if testOnlySet[to] && !mainSet[to] {
+				relationships = append(relationships, spdxRelationship{
+					SPDXElementID:      ids[to],
+					RelationshipType:   "TEST_DEPENDENCY_OF",
+					RelatedSPDXElement: ids[from],
+				})
+				continue
+			}
+			relationships = append(relationships, spdxRelationship{
+				SPDXElementID:      ids[from],
+				RelationshipType:   "DEPENDS_ON",
+				RelatedSPDXElement: ids[to],
+			})
+		}
+	}
+
+	creator := "Tool: " + info.Tool
+	if info.Version != "" {
+		creator += "-" + info.Version
+	}
+	return spdxDocument{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              overview.MainModules[0],
+		DocumentNamespace: "https://sigs.k8s.io/depstat/spdx/" + spdxIDSafe(overview.MainModules[0]) + "-" + info.UUID,
+		CreationInfo: spdxCreationInfo{
+			Created:  info.Timestamp.Format(time.RFC3339),
+			Creators: []string{creator},
+		},
+		Packages:      packages,
+		Relationships: relationships,
+	}
+}
+
+// spdxIDs assigns a unique SPDX identifier to every module. Identifiers are
+// derived from the module path and suffixed with a counter on collision.
+func spdxIDs(modules []string) map[string]string {
+	ids := make(map[string]string, len(modules))
+	used := map[string]bool{}
+	for _, mod := range modules {
+		base := "SPDXRef-Package-" + spdxIDSafe(mod)
+		id := base
+		for i := 2; used[id]; i++ {
+			id = fmt.Sprintf("%s-%d", base, i)
+		}
+		used[id] = true
+		ids[mod] = id
+	}
+	return ids
+}
+
+// spdxIDSafe replaces characters that are not allowed in an SPDX
+// identifier ([A-Za-z0-9.-]) with '-'.
+func spdxIDSafe(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '.' || r == '-' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('-')
+		}
+	}
+	return b.String()
+}
+
+// goPurl returns the package URL for a Go module, e.g.
+// pkg:golang/github.com/spf13/cobra@v1.8.1. The version is omitted when
+// unknown, which is the case for main modules.
+func goPurl(modulePath, version string) string {
+	segments := strings.Split(modulePath, "/")
+	for i, s := range segments {
+		segments[i] = purlEscape(s)
+	}
+	purl := "pkg:golang/" + strings.Join(segments, "/")
+	if version != "" {
+		purl += "@" + purlEscape(version)
+	}
+	return purl
+}
+
+// purlEscape percent-encodes everything outside the unreserved set, which
+// covers the '+' in "+incompatible" versions.
+func purlEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9') || c == '.' || c == '-' || c == '_' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+// newUUID returns a random RFC 4122 version 4 UUID.
+func newUUID() (string, error) {
+	var u [16]byte
+	if _, err := rand.Read(u[:]); err != nil {
+		return "", err
+	}
+	u[6] = (u[6] & 0x0f) | 0x40
+	u[8] = (u[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16]), nil
+}
+
+func init() {
+	rootCmd.AddCommand(sbomCmd)
+	sbomCmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory containing the module to evaluate. Defaults to the current directory.")
+	sbomCmd.Flags().StringVar(&sbomFormat, "format", sbomFormatCycloneDX, "SBOM format: cyclonedx-json or spdx-json")
+	sbomCmd.Flags().StringVar(&sbomOutputPath, "output", "", "Write the SBOM to this file instead of stdout")
+	sbomCmd.Flags().BoolVar(&sbomSkipTestScope, "skip-test-scope", false, "Do not classify test-only dependencies (skips go mod why -m)")
+	sbomCmd.Flags().StringSliceVar(&excludeModules, "exclude-modules", []string{}, "Exclude module path patterns (repeatable, supports * wildcard)")
+	sbomCmd.Flags().StringSliceVarP(&mainModules, "mainModules", "m", []string{}, "Specify main modules")
+}
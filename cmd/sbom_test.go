@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+func sbomTestOverview() *DependencyOverview {
+	return &DependencyOverview{
+		MainModules:   []string{"example.com/root", "example.com/staging"},
+		DirectDepList: []string{"example.com/a", "example.com/t"},
+		TransDepList:  []string{"example.com/c", "example.com/root"},
+		Graph: map[string][]string{
+			"example.com/root":    {"example.com/a", "example.com/t"},
+			"example.com/staging": {"example.com/a"},
+			"example.com/a":       {"example.com/c", "example.com/root"},
+		},
+		Versions: map[string]string{
+			"example.com/root":    "",
+			"example.com/staging": "",
+			"example.com/a":       "v1.2.0",
+			"example.com/c":       "v2.0.0+incompatible",
+			"example.com/t":       "v0.1.0",
+		},
+	}
+}
+
+func sbomTestInfo() sbomInfo {
+	return sbomInfo{
+		Timestamp: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		UUID:      "3f1b1f0e-8d2a-4c1e-9b7a-2d7c0a9e5f10",
+		Tool:      "depstat",
+		Version:   "v0.0.0-test",
+	}
+}
+
+// roundTrip marshals v and decodes it back into a generic JSON value, so the
+// tests see the document as a consumer would.
+func roundTrip(t *testing.T, v interface{}) map[string]interface{} {
+	t.Helper()
+	raw, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return out
+}
+
+func requireKeys(t *testing.T, where string, obj map[string]interface{}, keys ...string) {
+	t.Helper()
+	for _, k := range keys {
+		if _, ok := obj[k]; !ok {
+			t.Fatalf("%s: missing required property %q in %v", where, k, obj)
+		}
+	}
+}
+
+var purlRe = regexp.MustCompile(`^pkg:golang/[A-Za-z0-9.\-_~%]+(/[A-Za-z0-9.\-_~%]+)*(@[A-Za-z0-9.\-_~%]+)?$`)
+
+// cycloneDXSchema compiles the published CycloneDX 1.5 JSON schema vendored
+// under testdata/schema, along with the SPDX license and JSF schemas it
+// references.
+func cycloneDXSchema(t *testing.T) *jsonschema.Schema {
+	t.Helper()
+	c := jsonschema.NewCompiler()
+	c.Draft = jsonschema.Draft7
+	c.AssertFormat = true
+	for _, name := range []string{"bom-1.5.schema.json", "spdx.schema.json", "jsf-0.82.schema.json"} {
+		f, err := os.Open(filepath.Join("testdata", "schema", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := c.AddResource("http://cyclonedx.org/schema/"+name, f); err != nil {
+			t.Fatalf("loading %s: %v", name, err)
+		}
+	}
+	schema, err := c.Compile("http://cyclonedx.org/schema/bom-1.5.schema.json")
+	if err != nil {
+		t.Fatalf("compiling the CycloneDX schema: %v", err)
+	}
+	return schema
+}
+
+func TestBuildCycloneDXSchema(t *testing.T) {
+	schema := cycloneDXSchema(t)
+	for _, skipTest := range []map[string]bool{nil, {"example.com/t": true}} {
+		doc := roundTrip(t, buildCycloneDX(sbomTestOverview(), skipTest, sbomTestInfo()))
+		if err := schema.Validate(doc); err != nil {
+			t.Fatalf("document does not validate against bom-1.5.schema.json: %#v", err)
+		}
+	}
+
+	// The schema has to catch a broken document for the check to mean
+	// anything.
+	doc := roundTrip(t, buildCycloneDX(sbomTestOverview(), nil, sbomTestInfo()))
+	doc["components"].([]interface{})[0].(map[string]interface{})["scope"] = "test"
+	if err := schema.Validate(doc); err == nil {
+		t.Fatal("expected an invalid component scope to fail validation")
+	}
+}
+
+// TestBuildCycloneDXRequiredFields checks what the schema cannot: that the
+// component types and scopes depstat picks are the intended ones and that
+// every dependency refers to a declared bom-ref.
+func TestBuildCycloneDXRequiredFields(t *testing.T) {
+	doc := roundTrip(t, buildCycloneDX(sbomTestOverview(), map[string]bool{"example.com/t": true}, sbomTestInfo()))
+
+	// Required properties and constants from bom-1.5.schema.json.
+	requireKeys(t, "bom", doc, "bomFormat", "specVersion")
+	if doc["bomFormat"] != "CycloneDX" || doc["specVersion"] != "1.5" {
+		t.Fatalf("unexpected bomFormat/specVersion: %v %v", doc["bomFormat"], doc["specVersion"])
+	}
+	if !regexp.MustCompile(`^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-[1-5][0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`).MatchString(doc["serialNumber"].(string)) {
+		t.Fatalf("serialNumber does not match the CycloneDX serialNumber pattern: %v", doc["serialNumber"])
+	}
+
+	refs := map[string]bool{}
+	var checkComponent func(c map[string]interface{})
+	checkComponent = func(c map[string]interface{}) {
+		requireKeys(t, "component", c, "type", "name")
+		switch c["type"] {
+		case "application", "library":
+		default:
+			t.Fatalf("unexpected component type %v", c["type"])
+		}
+		if scope, ok := c["scope"]; ok && scope != "required" && scope != "optional" && scope != "excluded" {
+			t.Fatalf("invalid scope %v", scope)
+		}
+		purl := c["purl"].(string)
+		if !purlRe.MatchString(purl) {
+			t.Fatalf("invalid purl %q", purl)
+		}
+		ref := c["bom-ref"].(string)
+		if refs[ref] {
+			t.Fatalf("duplicate bom-ref %q", ref)
+		}
+		refs[ref] = true
+		if nested, ok := c["components"].([]interface{}); ok {
+			for _, n := range nested {
+				checkComponent(n.(map[string]interface{}))
+			}
+		}
+	}
+
+	metadata := doc["metadata"].(map[string]interface{})
+	root := metadata["component"].(map[string]interface{})
+	if root["name"] != "example.com/root" || root["type"] != "application" {
+		t.Fatalf("unexpected root component: %v", root)
+	}
+	checkComponent(root)
+	for _, c := range doc["components"].([]interface{}) {
+		checkComponent(c.(map[string]interface{}))
+	}
+	if len(refs) != 5 {
+		t.Fatalf("expected 5 components (2 main + 3 deps), got %d: %v", len(refs), refs)
+	}
+
+	scopes := map[string]string{}
+	for _, c := range doc["components"].([]interface{}) {
+		cm := c.(map[string]interface{})
+		scopes[cm["name"].(string)], _ = cm["scope"].(string)
+	}
+	if scopes["example.com/t"] != "excluded" || scopes["example.com/a"] != "required" {
+		t.Fatalf("unexpected scopes: %v", scopes)
+	}
+
+	for _, d := range doc["dependencies"].([]interface{}) {
+		dm := d.(map[string]interface{})
+		requireKeys(t, "dependency", dm, "ref")
+		if !refs[dm["ref"].(string)] {
+			t.Fatalf("dependency ref %v is not a declared bom-ref", dm["ref"])
+		}
+		for _, on := range dm["dependsOn"].([]interface{}) {
+			if !refs[on.(string)] {
+				t.Fatalf("dependsOn %v is not a declared bom-ref", on)
+			}
+		}
+	}
+}
+
+// TestBuildSPDXRequiredFields checks the required properties of SPDX 2.3
+// that depstat relies on. It does not validate the document against the full
+// published schema.
+func TestBuildSPDXRequiredFields(t *testing.T) {
+	doc := roundTrip(t, buildSPDX(sbomTestOverview(), map[string]bool{"example.com/t": true}, sbomTestInfo()))
+
+	// Required properties from spdx-schema.json (SPDX 2.3).
+	requireKeys(t, "document", doc, "SPDXID", "creationInfo", "dataLicense", "name", "spdxVersion", "documentNamespace")
+	if doc["spdxVersion"] != "SPDX-2.3" || doc["dataLicense"] != "CC0-1.0" || doc["SPDXID"] != "SPDXRef-DOCUMENT" {
+		t.Fatalf("unexpected document header: %v", doc)
+	}
+	requireKeys(t, "creationInfo", doc["creationInfo"].(map[string]interface{}), "created", "creators")
+
+	idRe := regexp.MustCompile(`^SPDXRef-[A-Za-z0-9.\-]+$`)
+	ids := map[string]bool{"SPDXRef-DOCUMENT": true}
+	for _, p := range doc["packages"].([]interface{}) {
+		pm := p.(map[string]interface{})
+		requireKeys(t, "package", pm, "SPDXID", "name", "downloadLocation")
+		id := pm["SPDXID"].(string)
+		if !idRe.MatchString(id) {
+			t.Fatalf("invalid SPDXID %q", id)
+		}
+		if ids[id] {
+			t.Fatalf("duplicate SPDXID %q", id)
+		}
+		ids[id] = true
+		for _, r := range pm["externalRefs"].([]interface{}) {
+			rm := r.(map[string]interface{})
+			requireKeys(t, "externalRef", rm, "referenceCategory", "referenceLocator", "referenceType")
+			if !purlRe.MatchString(rm["referenceLocator"].(string)) {
+				t.Fatalf("invalid purl %v", rm["referenceLocator"])
+			}
+		}
+	}
+
+	types := map[string]int{}
+	for _, r := range doc["relationships"].([]interface{}) {
+		rm := r.(map[string]interface{})
+		requireKeys(t, "relationship", rm, "spdxElementId", "relatedSpdxElement", "relationshipType")
+		if !ids[rm["spdxElementId"].(string)] || !ids[rm["relatedSpdxElement"].(string)] {
+			t.Fatalf("relationship references unknown element: %v", rm)
+		}
+		types[rm["relationshipType"].(string)]++
+	}
+	// 2 DESCRIBES for the main modules, 1 TEST_DEPENDENCY_OF for root -> t,
+	// and DEPENDS_ON for the remaining 4 edges.
+	if types["DESCRIBES"] != 2 || types["TEST_DEPENDENCY_OF"] != 1 || types["DEPENDS_ON"] != 4 {
+		t.Fatalf("unexpected relationship types: %v", types)
+	}
+}
+
+func TestGoPurl(t *testing.T) {
+	tests := []struct {
+		path, version, want string
+	}{
+		{"github.com/spf13/cobra", "v1.8.1", "pkg:golang/github.com/spf13/cobra@v1.8.1"},
+		{"github.com/docker/docker", "v20.10.0+incompatible", "pkg:golang/github.com/docker/docker@v20.10.0%2Bincompatible"},
+		{"example.com/root", "", "pkg:golang/example.com/root"},
+	}
+	for _, tt := range tests {
+		if got := goPurl(tt.path, tt.version); got != tt.want {
+			t.Errorf("goPurl(%q, %q) = %q, want %q", tt.path, tt.version, got, tt.want)
+		}
+	}
+}
+
+func TestSPDXIDsUnique(t *testing.T) {
+	ids := spdxIDs([]string{"example.com/a_b", "example.com/a-b"})
+	if ids["example.com/a_b"] == ids["example.com/a-b"] {
+		t.Fatalf("expected unique ids, got %v", ids)
+	}
+}
@@ -0,0 +1,510 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// svgCrossingSweeps is the number of down/up barycenter sweeps used to
+// reduce edge crossings between adjacent layers.
+const svgCrossingSweeps = 12
+
+// layeredLayout is the result of laying out a directed graph top-down in
+// layers, Sugiyama style.
+type layeredLayout struct {
+	LayerOf   map[string]int
+	Layers    [][]string
+	Positions map[string]nodePos
+	Width     float64
+	Height    float64
+}
+
+// layoutOptions controls layoutLayered.
+type layoutOptions struct {
+	// Roots are placed in layer 0 and drive cycle breaking; nodes without
+	// incoming edges are treated as roots as well.
+	Roots []string
+	// Sinks are pushed to the bottom layer (e.g. the target of "why").
+	Sinks []string
+	// Widths of each node box; missing entries use svgMinNodeWidth.
+	Widths map[string]float64
+	// Top is the y coordinate of layer 0.
+	Top float64
+	// MaxWidth caps the canvas width (0 = unbounded).
+	MaxWidth float64
+}
+
+// layoutLayered assigns layers by longest path from the roots, orders nodes
+// within each layer with the barycenter heuristic to reduce crossings and
+// computes box positions with every layer centered on the canvas.
+func layoutLayered(nodes []string, edges []svgEdge, opts layoutOptions) layeredLayout {
+	sortedNodes := append([]string{}, nodes...)
+	sort.Strings(sortedNodes)
+	nodeSet := make(map[string]bool, len(sortedNodes))
+	for _, n := range sortedNodes {
+		nodeSet[n] = true
+	}
+
+	succ := map[string][]string{}
+	hasIncoming := map[string]bool{}
+	for _, e := range edges {
+		if !nodeSet[e.From] || !nodeSet[e.To] || e.From == e.To {
+			continue
+		}
+		succ[e.From] = append(succ[e.From], e.To)
+		hasIncoming[e.To] = true
+	}
+	for n := range succ {
+		sort.Strings(succ[n])
+	}
+
+	// Break cycles by dropping DFS back edges, starting from the roots so
+	// that the "natural" direction away from the main modules is kept.
+	var starts []string
+	for _, r := range opts.Roots {
+		if nodeSet[r] {
+			starts = append(starts, r)
+		}
+	}
+	for _, n := range sortedNodes {
+		if !hasIncoming[n] {
+			starts = append(starts, n)
+		}
+	}
+	starts = append(starts, sortedNodes...)
+
+	dag := map[string][]string{}
+	state := map[string]int{} // 0 = unvisited, 1 = on stack, 2 = done
+	var topo []string
+	var visit func(n string)
+	visit = func(n string) {
+		state[n] = 1
+		for _, next := range succ[n] {
+			switch state[next] {
+			case 0:
+				dag[n] = append(dag[n], next)
+				visit(next)
+			case 2:
+				dag[n] = append(dag[n], next)
+			}
+			// state 1: back edge, dropped
+		}
+		state[n] = 2
+		topo = append(topo, n)
+	}
+	for _, n := range starts {
+		if state[n] == 0 {
+			visit(n)
+		}
+	}
+	// topo is in post-order; reverse it for a topological order.
+	for i, j := 0, len(topo)-1; i < j; i, j = i+1, j-1 {
+		topo[i], topo[j] = topo[j], topo[i]
+	}
+
+	// Longest-path layering on the DAG.
+	layerOf := make(map[string]int, len(sortedNodes))
+	for _, n := range topo {
+		if _, ok := layerOf[n]; !ok {
+			layerOf[n] = 0
+		}
+		for _, next := range dag[n] {
+			if layerOf[n]+1 > layerOf[next] {
+				layerOf[next] = layerOf[n] + 1
+			}
+		}
+	}
+	maxLayer := 0
+	for _, l := range layerOf {
+		if l > maxLayer {
+			maxLayer = l
+		}
+	}
+	for _, s := range opts.Sinks {
+		if nodeSet[s] {
+			layerOf[s] = maxLayer
+		}
+	}
+
+	layers := make([][]string, maxLayer+1)
+	for _, n := range sortedNodes {
+		layers[layerOf[n]] = append(layers[layerOf[n]], n)
+	}
+	if len(sortedNodes) == 0 {
+		layers = nil
+	}
+
+	pred := map[string][]string{}
+	for from, tos := range dag {
+		for _, to := range tos {
+			pred[to] = append(pred[to], from)
+		}
+	}
+	orderLayers(layers, pred, dag, layerOf)
+
+	widths := make(map[string]float64, len(sortedNodes))
+	for _, n := range sortedNodes {
+		w := opts.Widths[n]
+		if w == 0 {
+			w = svgMinNodeWidth
+		}
+		widths[n] = w
+	}
+	maxLayerWidth := 0.0
+	for _, layer := range layers {
+		tw := 0.0
+		for _, n := range layer {
+			tw += widths[n]
+		}
+		tw += float64(len(layer)-1) * svgNodeSpacing
+		if tw > maxLayerWidth {
+			maxLayerWidth = tw
+		}
+	}
+	width := math.Max(svgMinWidth, maxLayerWidth+2*svgPaddingX)
+	if opts.MaxWidth > 0 {
+		width = math.Min(opts.MaxWidth, width)
+	}
+	height := opts.Top + float64(len(layers)-1)*svgLayerSpacing + svgNodeHeight + 40
+
+	positions := make(map[string]nodePos, len(sortedNodes))
+	for l, layer := range layers {
+		totalW := 0.0
+		for _, n := range layer {
+			totalW += widths[n]
+		}
+		totalW += float64(len(layer)-1) * svgNodeSpacing
+		x := (width - totalW) / 2
+		y := opts.Top + float64(l)*svgLayerSpacing
+		for _, n := range layer {
+			positions[n] = nodePos{X: x, Y: y, W: widths[n], H: svgNodeHeight}
+			x += widths[n] + svgNodeSpacing
+		}
+	}
+
+	return layeredLayout{
+		LayerOf:   layerOf,
+		Layers:    layers,
+		Positions: positions,
+		Width:     width,
+		Height:    height,
+	}
+}
+
+// orderLayers reorders nodes within each layer in place using alternating
+// down and up barycenter sweeps, keeping the ordering with the fewest
+// crossings between adjacent layers.
+func orderLayers(layers [][]string, pred, succ map[string][]string, layerOf map[string]int) {
+	if len(layers) < 2 {
+		return
+	}
+	rel := map[string]float64{}
+	updateRel := func(layer []string) {
+		for i, n := range layer {
+			rel[n] = (float64(i) + 0.5) / float64(len(layer))
+		}
+	}
+	for _, layer := range layers {
+		updateRel(layer)
+	}
+
+	snapshot := func() [][]string {
+		out := make([][]string, len(layers))
+		for i := range layers {
+			out[i] = append([]string{}, layers[i]...)
+		}
+		return out
+	}
+	best := snapshot()
+	bestCrossings := countLayerCrossings(layers, succ, layerOf)
+
+	reorder := func(layer []string, neighbours map[string][]string) {
+		bary := make(map[string]float64, len(layer))
+		for _, n := range layer {
+			if len(neighbours[n]) == 0 {
+				bary[n] = rel[n]
+				continue
+			}
+			sum := 0.0
+			for _, m := range neighbours[n] {
+				sum += rel[m]
+			}
+			bary[n] = sum / float64(len(neighbours[n]))
+		}
+		sort.SliceStable(layer, func(i, j int) bool { return bary[layer[i]] < bary[layer[j]] })
+		updateRel(layer)
+	}
+
+	for sweep := 0; sweep < svgCrossingSweeps && bestCrossings > 0; sweep++ {
+		for l := 1; l < len(layers); l++ {
+			reorder(layers[l], pred)
+		}
+		for l := len(layers) - 2; l >= 0; l-- {
+			reorder(layers[l], succ)
+		}
+		if c := countLayerCrossings(layers, succ, layerOf); c < bestCrossings {
+			bestCrossings = c
+			best = snapshot()
+		}
+	}
+	for i := range layers {
+		copy(layers[i], best[i])
+	}
+}
+
+// countLayerCrossings counts crossings between edges that connect adjacent
+// layers. Longer edges are ignored, which is enough to compare orderings.
+func countLayerCrossings(layers [][]string, succ map[string][]string, layerOf map[string]int) int {
+	index := map[string]int{}
+	for _, layer := range layers {
+		for i, n := range layer {
+			index[n] = i
+		}
+	}
+	crossings := 0
+	for l := 0; l+1 < len(layers); l++ {
+		var spans [][2]int
+		for _, n := range layers[l] {
+			for _, m := range succ[n] {
+				if layerOf[m] == l+1 {
+					spans = append(spans, [2]int{index[n], index[m]})
+				}
+			}
+		}
+		crossings += countSpanInversions(spans, len(layers[l+1]))
+	}
+	return crossings
+}
+
+// countSpanInversions counts the pairs of spans that cross, i.e. whose
+// sources and targets are in strictly opposite order. Targets must be below
+// width. Spans are sorted by source, then target, and a Fenwick tree over
+// the targets counts the earlier spans that end further right, in
+// O(E log E) rather than comparing every pair.
+func countSpanInversions(spans [][2]int, width int) int {
+	sort.Slice(spans, func(i, j int) bool {
+		if spans[i][0] != spans[j][0] {
+			return spans[i][0] < spans[j][0]
+		}
+		return spans[i][1] < spans[j][1]
+	})
+	tree := make([]int, width+1)
+	inversions := 0
+	for seen, s := range spans {
+		// Earlier spans with a target at or left of this one do not cross it.
+		notCrossing := 0
+		for i := s[1] + 1; i > 0; i -= i & -i {
+			notCrossing += tree[i]
+		}
+		inversions += seen - notCrossing
+		for i := s[1] + 1; i <= width; i += i & -i {
+			tree[i]++
+		}
+	}
+	return inversions
+}
+
+// svgNodeStyle describes how a node box is drawn.
+type svgNodeStyle struct {
+	Label       string
+	Color       nodeColor
+	StrokeWidth string
+	Dashed      bool
+}
+
+// svgEdgeStyle describes how an edge is drawn.
+type svgEdgeStyle struct {
+	Stroke string
+	Width  string
+	Dashed bool
+	// Label is drawn next to the middle of the edge.
+	Label string
+}
+
+type svgLegendEntry struct {
+	Fill, Stroke, Label string
+}
+
+// svgDiagram is a layered graph ready to be rendered as a standalone SVG.
+type svgDiagram struct {
+	Title     string
+	Subtitle  string
+	Nodes     []string
+	Edges     []svgEdge
+	Layout    layoutOptions
+	Legend    []svgLegendEntry
+	NodeStyle func(node string) svgNodeStyle
+	// EdgeStyle receives the number of layers the edge spans.
+	EdgeStyle func(e svgEdge, layerDiff int) svgEdgeStyle
+}
+
+// renderSVGDiagram lays out d and renders it as a self-contained SVG document.
+func renderSVGDiagram(d svgDiagram) string {
+	content, width, height := renderSVGContent(d, "")
+	return svgDocument(content, width, height)
+}
+
+// renderSVGStack renders several diagrams one below the other in a single
+// SVG document, each centered horizontally.
+func renderSVGStack(diagrams []svgDiagram) string {
+	contents := make([]string, len(diagrams))
+	widths := make([]float64, len(diagrams))
+	heights := make([]float64, len(diagrams))
+	width, height := 0.0, 0.0
+	for i, d := range diagrams {
+		// Marker IDs must be unique across the whole document.
+		contents[i], widths[i], heights[i] = renderSVGContent(d, fmt.Sprintf("d%d", i))
+		width = math.Max(width, widths[i])
+		height += heights[i]
+	}
+	var b strings.Builder
+	y := 0.0
+	for i := range diagrams {
+		fmt.Fprintf(&b, "<g transform=\"translate(%.1f,%.1f)\">\n", (width-widths[i])/2, y)
+		b.WriteString(contents[i])
+		fmt.Fprintln(&b, "</g>")
+		y += heights[i]
+	}
+	return svgDocument(b.String(), width, height)
+}
+
+// svgDocument wraps content in an <svg> element of the given size.
+func svgDocument(content string, width, height float64) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="system-ui,-apple-system,sans-serif">`, width, height, width, height)
+	fmt.Fprintln(&b)
+	b.WriteString(content)
+	fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" text-anchor="middle" font-size="10" fill="#aaa">generated by depstat</text>`,
+		width/2, height-12)
+	fmt.Fprintln(&b)
+	fmt.Fprintln(&b, `</svg>`)
+	return b.String()
+}
+
+// renderSVGContent lays out d and renders its elements without the
+// enclosing <svg> element. idPrefix is prepended to marker IDs.
+func renderSVGContent(d svgDiagram, idPrefix string) (string, float64, float64) {
+	styles := make(map[string]svgNodeStyle, len(d.Nodes))
+	widths := make(map[string]float64, len(d.Nodes))
+	for _, n := range d.Nodes {
+		s := d.NodeStyle(n)
+		if s.Label == "" {
+			s.Label = n
+		}
+		if s.StrokeWidth == "" {
+			s.StrokeWidth = "1.5"
+		}
+		styles[n] = s
+		widths[n] = math.Max(svgMinNodeWidth, float64(len(s.Label))*svgCharWidth+24)
+	}
+	opts := d.Layout
+	opts.Widths = widths
+	if opts.Top == 0 {
+		opts.Top = svgPaddingTop
+	}
+	layout := layoutLayered(d.Nodes, d.Edges, opts)
+
+	// Sort edges for deterministic output and collect marker colours.
+	edges := append([]svgEdge{}, d.Edges...)
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].From == edges[j].From {
+			return edges[i].To < edges[j].To
+		}
+		return edges[i].From < edges[j].From
+	})
+	edgeStyles := make([]svgEdgeStyle, len(edges))
+	markerIDs := map[string]string{}
+	var markerColors []string
+	for i, e := range edges {
+		s := d.EdgeStyle(e, layout.LayerOf[e.To]-layout.LayerOf[e.From])
+		if s.Stroke == "" {
+			s.Stroke = "#888"
+		}
+		if s.Width == "" {
+			s.Width = "1.3"
+		}
+		edgeStyles[i] = s
+		if _, ok := markerIDs[s.Stroke]; !ok {
+			markerIDs[s.Stroke] = fmt.Sprintf("%sm%d", idPrefix, len(markerColors))
+			markerColors = append(markerColors, s.Stroke)
+		}
+	}
+
+	var b strings.Builder
+	fmt.Fprintln(&b, "<defs>")
+	for _, c := range markerColors {
+		fmt.Fprintf(&b, `  <marker id="%s" viewBox="0 0 10 6" refX="10" refY="3" markerWidth="8" markerHeight="5" orient="auto-start-reverse">
+    <path d="M0 0L10 3L0 6z" fill="%s"/>
+  </marker>
+`, markerIDs[c], c)
+	}
+	fmt.Fprintln(&b, "</defs>")
+
+	if d.Title != "" {
+		fmt.Fprintf(&b, `<text x="%.1f" y="28" text-anchor="middle" font-size="14" font-weight="600" fill="#333">%s</text>`, layout.Width/2, xmlEscape(d.Title))
+		fmt.Fprintln(&b)
+	}
+	if d.Subtitle != "" {
+		fmt.Fprintf(&b, `<text x="%.1f" y="46" text-anchor="middle" font-size="11" fill="#888">%s</text>`, layout.Width/2, xmlEscape(d.Subtitle))
+		fmt.Fprintln(&b)
+	}
+	if len(d.Legend) > 0 {
+		renderSVGLegend(&b, 16, 60, d.Legend)
+	}
+
+	// Edges before nodes so nodes draw on top
+	for i, e := range edges {
+		s := edgeStyles[i]
+		dash := ""
+		if s.Dashed {
+			dash = ` stroke-dasharray="5,3"`
+		}
+		from, to := layout.Positions[e.From], layout.Positions[e.To]
+		fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="%s" stroke-width="%s" marker-end="url(#%s)"%s/>`,
+			svgBezierPath(from, to), s.Stroke, s.Width, markerIDs[s.Stroke], dash)
+		fmt.Fprintln(&b)
+		if s.Label != "" {
+			x, y := svgEdgeLabelPoint(from, to)
+			fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central" font-size="10" fill="%s" stroke="#fff" stroke-width="3" paint-order="stroke">%s</text>`,
+				x, y, s.Stroke, xmlEscape(s.Label))
+			fmt.Fprintln(&b)
+		}
+	}
+
+	sortedNodes := append([]string{}, d.Nodes...)
+	sort.Strings(sortedNodes)
+	for _, node := range sortedNodes {
+		p := layout.Positions[node]
+		s := styles[node]
+		dash := ""
+		if s.Dashed {
+			dash = ` stroke-dasharray="5,3"`
+		}
+		fmt.Fprintf(&b, `<g><title>%s</title>`, xmlEscape(node))
+		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.0f" fill="%s" stroke="%s" stroke-width="%s"%s/>`,
+			p.X, p.Y, p.W, p.H, svgCornerRadius, s.Color.Fill, s.Color.Stroke, s.StrokeWidth, dash)
+		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central" font-size="%.0f" fill="%s">%s</text>`,
+			p.X+p.W/2, p.Y+p.H/2, svgFontSize, s.Color.Text, xmlEscape(s.Label))
+		fmt.Fprintln(&b, `</g>`)
+	}
+
+	return b.String(), layout.Width, layout.Height
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/xml"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+func Test_layoutLayered_longestPathWithCycle(t *testing.T) {
+	edges := []svgEdge{
+		{"main", "A"}, {"main", "B"},
+		{"A", "C"}, {"B", "C"},
+		{"C", "D"}, {"D", "A"}, // cycle A -> C -> D -> A
+	}
+	layout := layoutLayered([]string{"main", "A", "B", "C", "D"}, edges, layoutOptions{Roots: []string{"main"}, Top: svgPaddingTop})
+
+	want := map[string]int{"main": 0, "A": 1, "B": 1, "C": 2, "D": 3}
+	for n, l := range want {
+		if layout.LayerOf[n] != l {
+			t.Errorf("layer of %s = %d, want %d", n, layout.LayerOf[n], l)
+		}
+	}
+	for n, p := range layout.Positions {
+		if p.X < 0 || p.X+p.W > layout.Width {
+			t.Errorf("node %s is outside the canvas: %+v (width %.0f)", n, p, layout.Width)
+		}
+	}
+}
+
+func Test_layoutLayered_sinks(t *testing.T) {
+	edges := []svgEdge{{"main", "T"}, {"main", "A"}, {"A", "B"}, {"B", "T"}}
+	layout := layoutLayered([]string{"main", "A", "B", "T"}, edges, layoutOptions{Roots: []string{"main"}, Sinks: []string{"T"}})
+	if layout.LayerOf["T"] != 3 {
+		t.Fatalf("expected sink T in bottom layer 3, got %d", layout.LayerOf["T"])
+	}
+}
+
+func Test_layoutLayered_reducesCrossings(t *testing.T) {
+	// Alphabetical order puts a1/b1 in crossing positions:
+	//   main -> a, main -> b; a -> z1, b -> y1
+	edges := []svgEdge{{"main", "a"}, {"main", "b"}, {"a", "z1"}, {"b", "y1"}}
+	nodes := []string{"main", "a", "b", "y1", "z1"}
+	layout := layoutLayered(nodes, edges, layoutOptions{Roots: []string{"main"}})
+
+	succ := map[string][]string{"main": {"a", "b"}, "a": {"z1"}, "b": {"y1"}}
+	if c := countLayerCrossings(layout.Layers, succ, layout.LayerOf); c != 0 {
+		t.Fatalf("expected no crossings after ordering, got %d (layers %v)", c, layout.Layers)
+	}
+	if layout.Positions["z1"].X > layout.Positions["y1"].X {
+		t.Fatalf("expected z1 left of y1 to follow a/b, got layers %v", layout.Layers)
+	}
+}
+
+func Test_countSpanInversions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := 0; n < 200; n++ {
+		width := 1 + rng.Intn(8)
+		spans := make([][2]int, rng.Intn(30))
+		for i := range spans {
+			spans[i] = [2]int{rng.Intn(8), rng.Intn(width)}
+		}
+		want := 0
+		for i := range spans {
+			for j := i + 1; j < len(spans); j++ {
+				if (spans[i][0]-spans[j][0])*(spans[i][1]-spans[j][1]) < 0 {
+					want++
+				}
+			}
+		}
+		if got := countSpanInversions(spans, width); got != want {
+			t.Fatalf("countSpanInversions(%v) = %d, want %d", spans, got, want)
+		}
+	}
+}
+
+func Test_renderSVGDiagram(t *testing.T) {
+	d := svgDiagram{
+		Title: "T & <co>",
+		Nodes: []string{"main", "A"},
+		Edges: []svgEdge{{"main", "A"}},
+		NodeStyle: func(node string) svgNodeStyle {
+			return svgNodeStyle{Color: classifyNodeColor(node, "", []string{"main"})}
+		},
+		EdgeStyle: func(e svgEdge, layerDiff int) svgEdgeStyle {
+			return svgEdgeStyle{Stroke: "#123456"}
+		},
+	}
+	out := renderSVGDiagram(d)
+	if out != renderSVGDiagram(d) {
+		t.Fatal("SVG output is not deterministic")
+	}
+	if err := xml.Unmarshal([]byte(out), new(struct{})); err != nil {
+		t.Fatalf("SVG is not well-formed XML: %v\n%s", err, out)
+	}
+	for _, want := range []string{`fill="#123456"`, `stroke="#123456"`, "T &amp; &lt;co&gt;"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in SVG:\n%s", want, out)
+		}
+	}
+}
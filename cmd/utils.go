@@ -66,14 +66,11 @@ func getMainModule() string {
 }
 
 func getDepInfo(mainModules []string) *DependencyOverview {
-	// If no main modules specified, detect using "go list -m"
-	if len(mainModules) == 0 {
-		if mainMod := getMainModule(); mainMod != "" {
-			mainModules = []string{mainMod}
-		}
-	}
+	return getDepInfoFromGraph(getGoModGraph(), mainModules)
+}
 
-	// get output of "go mod graph" in a string
+// getGoModGraph returns the output of "go mod graph".
+func getGoModGraph() string {
 	goModGraph := exec.Command("go", "mod", "graph")
 	if dir != "" {
 		goModGraph.Dir = dir
@@ -82,7 +79,18 @@ func getDepInfo(mainModules []string) *DependencyOverview {
 	if err != nil {
 		log.Fatal(err)
 	}
-	goModGraphOutputString := string(goModGraphOutput)
+	return string(goModGraphOutput)
+}
+
+// getDepInfoFromGraph builds the dependency overview from "go mod graph"
+// output, for callers that also need the raw graph.
+func getDepInfoFromGraph(goModGraphOutputString string, mainModules []string) *DependencyOverview {
+	// If no main modules specified, detect using "go list -m"
+	if len(mainModules) == 0 {
+		if mainMod := getMainModule(); mainMod != "" {
+			mainModules = []string{mainMod}
+		}
+	}
 
 	// create a graph of dependencies from that output
 	depGraph := generateGraph(goModGraphOutputString, mainModules)
@@ -53,19 +53,11 @@ func Test_getChains_simple(t *testing.T) {
 		MainModules:   mainModules,
 	}
 
-	var chains []Chain
 	var temp Chain
 	longestChain := getLongestChain("A", graph, temp, map[string]Chain{})
 	maxDepth := len(longestChain)
-	getAllChains("A", graph, temp, &chains)
 	cycles := findAllCycles(graph)
 
-	correctChains := [][]string{
-		{"A", "B", "E", "F", "H"},
-		{"A", "C", "E", "F", "H"},
-		{"A", "D", "G"},
-	}
-
 	correctFileContentsForAllDeps := `MainNode [label="A", style="filled" color="yellow"]
 "MainNode" -> "B"
 "MainNode" -> "C"
@@ -80,8 +72,24 @@ func Test_getChains_simple(t *testing.T) {
 		t.Errorf("File contents for graph of all dependencies are wrong")
 	}
 
+	focusEdges, err := focusSubgraph(overview, "E", focusOptions{Descendants: true, MaxDepth: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chains, ok := focusChains(mainModules, focusEdges, focusChainLimit)
+	if !ok {
+		t.Fatal("too many chains")
+	}
+
+	correctChains := [][]string{
+		{"A", "B", "E", "F", "H"},
+		{"A", "C", "E", "F", "H"},
+	}
+	if len(chains) != len(correctChains) {
+		t.Errorf("Expected %d chains, got %d: %v", len(correctChains), len(chains), chains)
+	}
 	for i := range chains {
-		if !isSliceSame(chains[i], correctChains[i]) {
+		if i < len(correctChains) && !isSliceSame(chains[i], correctChains[i]) {
 			t.Errorf("Chains are not same")
 		}
 	}
@@ -144,11 +152,9 @@ func Test_getChains_cycle(t *testing.T) {
 		MainModules:   mainModules,
 	}
 
-	var chains []Chain
 	var temp Chain
 	longestChain := getLongestChain("A", graph, temp, map[string]Chain{})
 	maxDepth := len(longestChain)
-	getAllChains("A", graph, temp, &chains)
 	cycles := findAllCycles(graph)
 
 	correctFileContentsForAllDeps := `MainNode [label="A", style="filled" color="yellow"]
@@ -165,12 +171,23 @@ func Test_getChains_cycle(t *testing.T) {
 		t.Errorf("File contents for graph of all dependencies are wrong")
 	}
 
+	focusEdges, err := focusSubgraph(overview, "H", focusOptions{Descendants: true, MaxDepth: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chains, ok := focusChains(mainModules, focusEdges, focusChainLimit)
+	if !ok {
+		t.Fatal("too many chains")
+	}
+
 	correctChains := [][]string{
 		{"A", "B", "D", "F", "G", "H"},
-		{"A", "C", "E"},
+	}
+	if len(chains) != len(correctChains) {
+		t.Errorf("Expected %d chains, got %d: %v", len(correctChains), len(chains), chains)
 	}
 	for i := range chains {
-		if !isSliceSame(chains[i], correctChains[i]) {
+		if i < len(correctChains) && !isSliceSame(chains[i], correctChains[i]) {
 			t.Errorf("Chains are not same")
 		}
 	}
@@ -235,20 +252,11 @@ func Test_getChains_cycle_2(t *testing.T) {
 		MainModules:   mainModules,
 	}
 
-	var chains []Chain
 	var temp Chain
 	longestChain := getLongestChain("A", graph, temp, map[string]Chain{})
 	maxDepth := len(longestChain)
-	getAllChains("A", graph, temp, &chains)
 	cycles := findAllCycles(graph)
 
-	correctChains := [][]string{
-		{"A", "B", "C"},
-		{"A", "B", "C", "E", "F", "D"},
-		{"A", "C", "B"},
-		{"A", "C", "E", "F", "D"},
-	}
-
 	correctFileContentsForAllDeps := `MainNode [label="A", style="filled" color="yellow"]
 "MainNode" -> "B"
 "MainNode" -> "C"
@@ -263,8 +271,26 @@ func Test_getChains_cycle_2(t *testing.T) {
 		t.Errorf("File contents for graph of all dependencies are wrong")
 	}
 
+	focusEdges, err := focusSubgraph(overview, "B", focusOptions{Descendants: true, MaxDepth: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chains, ok := focusChains(mainModules, focusEdges, focusChainLimit)
+	if !ok {
+		t.Fatal("too many chains")
+	}
+
+	correctChains := [][]string{
+		{"A", "B", "C"},
+		{"A", "B", "C", "E", "F", "D"},
+		{"A", "C", "B"},
+		{"A", "C", "E", "F", "D"},
+	}
+	if len(chains) != len(correctChains) {
+		t.Errorf("Expected %d chains, got %d: %v", len(correctChains), len(chains), chains)
+	}
 	for i := range chains {
-		if !isSliceSame(chains[i], correctChains[i]) {
+		if i < len(correctChains) && !isSliceSame(chains[i], correctChains[i]) {
 			t.Errorf("Chains are not same")
 		}
 	}
@@ -20,6 +20,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -29,6 +30,8 @@ import (
 type WhyPath struct {
 	Path   []string `json:"path"`
 	Direct bool     `json:"direct"` // true if this is a direct dependency of a main module
+	// Packages is the import chain behind the path, with --packages.
+	Packages *PackageChain `json:"packages,omitempty"`
 }
 
 // WhyResult holds the result of why analysis
@@ -39,7 +42,12 @@ type WhyResult struct {
 	DirectDeps  []string  `json:"directDependents"` // modules that directly depend on target
 	MainModules []string  `json:"mainModules"`
 	Truncated   bool      `json:"truncated,omitempty"`
-	TotalPaths  int       `json:"totalPaths,omitempty"`
+	// TotalPaths counts every path, including the ones left out by
+	// --max-paths. It is only a lower bound if TotalPathsLowerBound is set,
+	// which happens when the modules between main modules and target form
+	// cycles.
+	TotalPaths           int  `json:"totalPaths,omitempty"`
+	TotalPathsLowerBound bool `json:"totalPathsLowerBound,omitempty"`
 }
 
 const (
@@ -50,9 +58,11 @@ const (
 var whyMaxPaths int
 
 var whyCmd = &cobra.Command{
-	Use:   "why <dependency>",
+	Use:   "why <dependency>...",
 	Short: "Show why a dependency is included",
 	Long: `Show all dependency paths from main module(s) to a specific dependency.
+Several dependencies or glob patterns can be given at once; the dependency
+graph is then loaded once and the results are grouped by module.
 
 This helps understand why a particular dependency exists in your project
 and which modules are pulling it in.
@@ -68,117 +78,150 @@ Examples:
   depstat why github.com/google/btree --dot | dot -Tsvg -o why.svg
 
   # Output as self-contained SVG
-  depstat why github.com/google/btree --svg > why.svg`,
-	Args: cobra.ExactArgs(1),
+  depstat why github.com/google/btree --svg > why.svg
+
+  # Output as Mermaid flowchart
+  depstat why github.com/google/btree --mermaid
+
+  # Show which packages import each other along every path
+  depstat why github.com/google/btree --packages
+
+  # List the fewest modules to drop to get rid of a dependency
+  depstat why github.com/google/btree --cut
+
+  # Output as Markdown for a pull request comment
+  depstat why github.com/google/btree --markdown
+
+  # Explain several modules at once; * matches within one path element
+  depstat why 'github.com/gogo/*' go.uber.org/zap --json`,
+	Args: cobra.MinimumNArgs(1),
 	RunE: runWhy,
 }
 
 func runWhy(cmd *cobra.Command, args []string) error {
-	target := args[0]
-
-	depGraph := getDepInfo(mainModules)
-
-	// Find all paths to the target
-	result := WhyResult{
-		Target:      target,
-		Found:       false,
-		MainModules: depGraph.MainModules,
+	if annotateOutput && !dotOutput {
+		return fmt.Errorf("--annotate requires --dot")
+	}
+	if attributesPath != "" && !annotateOutput {
+		return fmt.Errorf("--attributes requires --annotate")
+	}
+	if whyMarkdown && (jsonOutput || dotOutput || svgOutput || mermaidOutput) {
+		return fmt.Errorf("--markdown cannot be combined with --json, --dot, --svg or --mermaid")
+	}
+	if whyPackages && (dotOutput || svgOutput || mermaidOutput || whyMarkdown) {
+		return fmt.Errorf("--packages is only supported with text and --json output")
+	}
+	if whyCut && (dotOutput || svgOutput || mermaidOutput || whyMarkdown || whyPackages) {
+		return fmt.Errorf("--cut is only supported with text and --json output")
 	}
 
-	// Check if target exists in dependencies
+	depGraph := getDepInfo(mainModules)
 	allDeps := getAllDeps(depGraph.DirectDepList, depGraph.TransDepList)
-	for _, dep := range allDeps {
-		if dep == target {
-			result.Found = true
-			break
-		}
+	if whyCut {
+		return runWhyCut(depGraph, args, allDeps)
+	}
+	if len(args) > 1 || isModulePattern(args[0]) {
+		return runWhyTargets(depGraph, expandWhyTargets(args, allDeps))
 	}
 
+	target := args[0]
+	result := analyzeWhy(depGraph, allDeps, target)
 	if !result.Found {
 		if jsonOutput {
 			return outputWhyJSON(result)
 		}
+		if whyMarkdown {
+			fmt.Print(renderWhyMarkdown([]WhyResult{result}, nil, whyMarkdownBudget))
+			return nil
+		}
 		fmt.Printf("Dependency %q not found in the dependency graph.\n", target)
 		return nil
 	}
 
-	// Find all modules that directly depend on target
-	for from, tos := range depGraph.Graph {
-		for _, to := range tos {
-			if to == target {
-				result.DirectDeps = append(result.DirectDeps, from)
-			}
+	if whyPackages {
+		pkgGraph, err := loadPackageGraph(depGraph.MainModules)
+		if err != nil {
+			return err
 		}
+		addPackageChains(&result, pkgGraph)
 	}
-	sort.Strings(result.DirectDeps)
-
-	// Find all paths from main modules to target.
-	var allPaths [][]string
-	for _, mainMod := range depGraph.MainModules {
-		findAllPaths(mainMod, target, depGraph.Graph, []string{}, make(map[string]bool), &allPaths, whyMaxPaths)
-		if whyMaxPaths > 0 && len(allPaths) >= whyMaxPaths {
-			result.Truncated = true
-			break
-		}
-	}
-	for _, path := range allPaths {
-		isDirect := len(path) == 2 && contains(depGraph.MainModules, path[0])
-		result.Paths = append(result.Paths, WhyPath{
-			Path:   path,
-			Direct: isDirect,
-		})
-	}
-
-	// Sort paths by length (shortest first)
-	sort.Slice(result.Paths, func(i, j int) bool {
-		if len(result.Paths[i].Path) != len(result.Paths[j].Path) {
-			return len(result.Paths[i].Path) < len(result.Paths[j].Path)
-		}
-		return strings.Join(result.Paths[i].Path, " -> ") < strings.Join(result.Paths[j].Path, " -> ")
-	})
-	result.TotalPaths = len(result.Paths)
 
 	if jsonOutput {
 		return outputWhyJSON(result)
 	}
+	if whyMarkdown {
+		fmt.Print(renderWhyMarkdown([]WhyResult{result}, nil, whyMarkdownBudget))
+		return nil
+	}
 	if dotOutput {
-		return outputWhyDOT(result, depGraph)
+		var annotations *dotAnnotations
+		if annotateOutput {
+			var err error
+			if annotations, err = newDOTAnnotations(depGraph, whyPathModules(result), attributesPath); err != nil {
+				return err
+			}
+		}
+		return outputWhyDOT(result, annotations)
 	}
 	if svgOutput {
 		return outputWhySVG(result)
 	}
+	if mermaidOutput {
+		return outputWhyMermaid(result)
+	}
 	return outputWhyText(result)
 }
 
-// findAllPaths finds paths from start to target using DFS and appends to out.
-// If maxPaths > 0, search stops once out reaches maxPaths.
-func findAllPaths(start, target string, graph map[string][]string, currentPath []string, visited map[string]bool, out *[][]string, maxPaths int) {
-	if maxPaths > 0 && len(*out) >= maxPaths {
-		return
+// analyzeWhy finds the direct dependents of target and the paths from the
+// main modules to it. allDeps lists every module of the graph.
+func analyzeWhy(depGraph *DependencyOverview, allDeps []string, target string) WhyResult {
+	result := WhyResult{
+		Target:      target,
+		Found:       contains(allDeps, target),
+		MainModules: depGraph.MainModules,
+	}
+	if !result.Found {
+		return result
 	}
 
-	currentPath = append(currentPath, start)
-
-	if start == target {
-		// Found the target, append a copy of the path.
-		pathCopy := make([]string, len(currentPath))
-		copy(pathCopy, currentPath)
-		*out = append(*out, pathCopy)
-		return
+	// Find all modules that directly depend on target
+	for from, tos := range depGraph.Graph {
+		for _, to := range tos {
+			if to == target {
+				result.DirectDeps = append(result.DirectDeps, from)
+			}
+		}
 	}
+	sort.Strings(result.DirectDeps)
 
-	if visited[start] {
-		return
+	// Rank the paths from main modules to target, asking for one more than
+	// --max-paths to know whether the result is complete.
+	limit := 0
+	if whyMaxPaths > 0 {
+		limit = whyMaxPaths + 1
+	}
+	paths := kShortestPaths(depGraph.Graph, depGraph.MainModules, target, limit)
+	if whyMaxPaths > 0 && len(paths) > whyMaxPaths {
+		paths = paths[:whyMaxPaths]
+		result.Truncated = true
+	}
+	for _, path := range paths {
+		isDirect := len(path) == 2 && contains(depGraph.MainModules, path[0])
+		result.Paths = append(result.Paths, WhyPath{
+			Path:   path,
+			Direct: isDirect,
+		})
 	}
-	visited[start] = true
-	defer func() { visited[start] = false }()
 
-	for _, next := range graph[start] {
-		findAllPaths(next, target, graph, currentPath, visited, out, maxPaths)
-		if maxPaths > 0 && len(*out) >= maxPaths {
-			return
+	result.TotalPaths = len(result.Paths)
+	if result.Truncated {
+		count, exact := countPaths(depGraph.Graph, depGraph.MainModules, target)
+		if count > result.TotalPaths {
+			result.TotalPaths = count
 		}
+		result.TotalPathsLowerBound = !exact
 	}
+	return result
 }
 
 func outputWhyJSON(result WhyResult) error {
@@ -216,7 +259,11 @@ func outputWhyText(result WhyResult) error {
 	if len(pathsToShow) > whyDefaultTextPaths {
 		pathsToShow = pathsToShow[:whyDefaultTextPaths]
 	}
-	fmt.Printf("Dependency paths (showing %d of %d):\n", len(pathsToShow), len(result.Paths))
+	total := strconv.Itoa(result.TotalPaths)
+	if result.TotalPathsLowerBound {
+		total = "at least " + total
+	}
+	fmt.Printf("Dependency paths (showing %d of %s, shortest first):\n", len(pathsToShow), total)
 	fmt.Println()
 
 	for i, wp := range pathsToShow {
@@ -226,12 +273,15 @@ func outputWhyText(result WhyResult) error {
 			fmt.Printf("  %d. ", i+1)
 		}
 		fmt.Println(strings.Join(wp.Path, " -> "))
+		if whyPackages {
+			printPackageChain(wp.Packages)
+		}
 	}
 
 	if len(result.Paths) > len(pathsToShow) || result.Truncated {
 		fmt.Println()
 		if result.Truncated {
-			fmt.Printf("  (search truncated at --max-paths=%d)\n", whyMaxPaths)
+			fmt.Printf("  (kept the %d shortest paths; raise --max-paths to see more)\n", whyMaxPaths)
 		} else {
 			fmt.Printf("  (showing first %d in text output; use --json/--dot/--svg for full set)\n", whyDefaultTextPaths)
 		}
@@ -240,68 +290,146 @@ func outputWhyText(result WhyResult) error {
 	return nil
 }
 
-func outputWhyDOT(result WhyResult, depGraph *DependencyOverview) error {
-	fmt.Println("strict digraph {")
-	fmt.Printf("graph [overlap=false, label=\"Why: %s\", labelloc=t];\n", result.Target)
-	fmt.Println("node [shape=box, style=filled, fillcolor=white];")
-	fmt.Println()
-
-	// Collect all nodes and edges from paths
+// whyPathModules returns the modules on any of the results' paths, sorted.
+func whyPathModules(results ...WhyResult) []string {
 	nodes := make(map[string]bool)
-	edges := make(map[string]bool)
-
-	for _, wp := range result.Paths {
-		for i, node := range wp.Path {
-			nodes[node] = true
-			if i > 0 {
-				edge := fmt.Sprintf("%s -> %s", wp.Path[i-1], node)
-				edges[edge] = true
+	for _, result := range results {
+		for _, wp := range result.Paths {
+			for _, node := range wp.Path {
+				nodes[node] = true
 			}
 		}
 	}
-
-	// Output nodes with colors
-	fmt.Println("// Nodes")
 	nodeList := make([]string, 0, len(nodes))
 	for node := range nodes {
 		nodeList = append(nodeList, node)
 	}
 	sort.Strings(nodeList)
-	for _, node := range nodeList {
+	return nodeList
+}
+
+// whyPathEdges returns the edges on any of the results' paths, sorted.
+func whyPathEdges(results ...WhyResult) []mermaidEdge {
+	edges := make(map[mermaidEdge]bool)
+	for _, result := range results {
+		for _, wp := range result.Paths {
+			for i := 1; i < len(wp.Path); i++ {
+				edges[mermaidEdge{From: wp.Path[i-1], To: wp.Path[i]}] = true
+			}
+		}
+	}
+	edgeList := make([]mermaidEdge, 0, len(edges))
+	for e := range edges {
+		edgeList = append(edgeList, e)
+	}
+	sort.Slice(edgeList, func(i, j int) bool {
+		if edgeList[i].From == edgeList[j].From {
+			return edgeList[i].To < edgeList[j].To
+		}
+		return edgeList[i].From < edgeList[j].From
+	})
+	return edgeList
+}
+
+// whyTargets returns the set of targets of results.
+func whyTargets(results []WhyResult) map[string]bool {
+	targets := make(map[string]bool, len(results))
+	for _, result := range results {
+		targets[result.Target] = true
+	}
+	return targets
+}
+
+// outputWhyDOT prints the paths as a DOT graph. annotations may be nil.
+func outputWhyDOT(result WhyResult, annotations *dotAnnotations) error {
+	return writeWhyDOT("Why: "+result.Target, []WhyResult{result}, annotations)
+}
+
+// writeWhyDOT prints the paths of all results as a single DOT graph with
+// every target highlighted. annotations may be nil.
+func writeWhyDOT(title string, results []WhyResult, annotations *dotAnnotations) error {
+	fmt.Println("strict digraph {")
+	fmt.Printf("graph [overlap=false, label=\"%s\", labelloc=t];\n", title)
+	fmt.Println("node [shape=box, style=filled, fillcolor=white];")
+	fmt.Println()
+
+	targets := whyTargets(results)
+	var mainMods []string
+	if len(results) > 0 {
+		mainMods = results[0].MainModules
+	}
+
+	// Output nodes with colors
+	fmt.Println("// Nodes")
+	for _, node := range whyPathModules(results...) {
 		color := "white"
-		if node == result.Target {
-			color = "#ffffcc" // yellow for target
-		} else if contains(result.MainModules, node) {
+		if targets[node] {
+			color = "#ffffcc" // yellow for targets
+		} else if contains(mainMods, node) {
 			color = "#ccffcc" // green for main modules
 		}
+		if annotations != nil {
+			if color == "white" {
+				color = ""
+			}
+			fmt.Printf("\"%s\" [%s];\n", node, annotations.nodeAttributes(node, color))
+			continue
+		}
 		fmt.Printf("\"%s\" [fillcolor=\"%s\"];\n", node, color)
 	}
 	fmt.Println()
 
 	// Output edges
 	fmt.Println("// Edges")
-	edgeList := make([]string, 0, len(edges))
-	for edge := range edges {
-		edgeList = append(edgeList, edge)
-	}
-	sort.Strings(edgeList)
-	for _, edge := range edgeList {
-		parts := strings.Split(edge, " -> ")
-		if len(parts) == 2 {
-			fmt.Printf("\"%s\" -> \"%s\";\n", parts[0], parts[1])
-		}
+	for _, e := range whyPathEdges(results...) {
+		fmt.Printf("\"%s\" -> \"%s\";\n", e.From, e.To)
 	}
 
 	fmt.Println("}")
 	return nil
 }
 
+func outputWhyMermaid(result WhyResult) error {
+	return writeWhyMermaid("Why: "+result.Target, []WhyResult{result})
+}
+
+// writeWhyMermaid prints the paths of all results as a single Mermaid
+// flowchart with every target highlighted.
+func writeWhyMermaid(title string, results []WhyResult) error {
+	g := newMermaidGraph(title, "TD")
+	targets := whyTargets(results)
+	for _, result := range results {
+		g.AddNode(result.Target, "", "target")
+	}
+	var mainMods []string
+	if len(results) > 0 {
+		mainMods = results[0].MainModules
+	}
+	for _, node := range whyPathModules(results...) {
+		if contains(mainMods, node) && !targets[node] {
+			g.AddNode(node, "", "mainmod")
+		}
+	}
+	for _, e := range whyPathEdges(results...) {
+		g.AddEdge(e)
+	}
+
+	fmt.Print(g.String())
+	return nil
+}
+
 func init() {
 	rootCmd.AddCommand(whyCmd)
 	whyCmd.Flags().StringVarP(&dir, "dir", "d", "", "Directory containing the module to evaluate")
 	whyCmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output in JSON format")
 	whyCmd.Flags().BoolVarP(&dotOutput, "dot", "", false, "Output in DOT format for Graphviz")
+	whyCmd.Flags().BoolVar(&annotateOutput, "annotate", false, "With --dot, label nodes with versions, mark main modules and colour test-only modules")
+	whyCmd.Flags().StringVar(&attributesPath, "attributes", "", "With --annotate, JSON file mapping modules to a fill colour and extra label")
 	whyCmd.Flags().BoolVarP(&svgOutput, "svg", "s", false, "Output as self-contained SVG diagram")
-	whyCmd.Flags().IntVar(&whyMaxPaths, "max-paths", whyDefaultMaxPaths, "Maximum dependency paths to search. Set 0 for no limit")
+	whyCmd.Flags().BoolVar(&mermaidOutput, "mermaid", false, "Output as Mermaid flowchart")
+	whyCmd.Flags().BoolVar(&whyMarkdown, "markdown", false, "Output as Markdown for pull request comments, shortened to fit GitHub's comment size limit")
+	whyCmd.Flags().BoolVar(&whyPackages, "packages", false, "Show the package import chain behind each path, marking chains through test packages")
+	whyCmd.Flags().BoolVar(&whyCut, "cut", false, "Show the fewest modules to remove, preferring direct dependencies, to drop the dependency from the graph")
+	whyCmd.Flags().IntVar(&whyMaxPaths, "max-paths", whyDefaultMaxPaths, "Maximum dependency paths to return, shortest first. Set 0 for no limit")
 	whyCmd.Flags().StringSliceVarP(&mainModules, "mainModules", "m", []string{}, "Specify main modules")
 }
@@ -0,0 +1,331 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var whyCut bool
+
+// WhyCut is the smallest set of modules whose removal disconnects a
+// dependency from the main modules.
+type WhyCut struct {
+	Target      string   `json:"target"`
+	Found       bool     `json:"found"`
+	MainModules []string `json:"mainModules"`
+	// RequiredByMain lists the main modules requiring target themselves, in
+	// which case no cut exists and the requirement has to be dropped there.
+	RequiredByMain []string       `json:"requiredByMain,omitempty"`
+	Cut            []CutCandidate `json:"cut"`
+	// Removed lists every module that disappears from the graph with the
+	// cut, including the cut modules and target.
+	Removed []string `json:"removed"`
+}
+
+// CutCandidate is a module of a cut. AlsoRemoved lists the other modules
+// that only stay in the graph through it.
+type CutCandidate struct {
+	Module      string   `json:"module"`
+	Direct      bool     `json:"direct"`
+	RequiredBy  []string `json:"requiredBy"`
+	AlsoRemoved []string `json:"alsoRemoved"`
+}
+
+// WhyCutReport is the --cut output of why with several dependencies or
+// patterns, keyed by module path.
+type WhyCutReport struct {
+	MainModules []string          `json:"mainModules"`
+	Targets     map[string]WhyCut `json:"targets"`
+	Unmatched   []string          `json:"unmatched,omitempty"`
+}
+
+// findModuleCut computes a minimum vertex cut between the main modules and
+// target. Among the cuts with the fewest modules, the one with the most
+// direct dependencies is preferred, since those are the requirements the
+// main modules control.
+func findModuleCut(depGraph *DependencyOverview, allDeps []string, target string) WhyCut {
+	result := WhyCut{
+		Target:      target,
+		Found:       contains(allDeps, target),
+		MainModules: depGraph.MainModules,
+		Cut:         []CutCandidate{},
+		Removed:     []string{},
+	}
+	if !result.Found || contains(depGraph.MainModules, target) {
+		return result
+	}
+	for _, m := range depGraph.MainModules {
+		if contains(depGraph.Graph[m], target) {
+			result.RequiredByMain = append(result.RequiredByMain, m)
+		}
+	}
+	if len(result.RequiredByMain) > 0 {
+		sort.Strings(result.RequiredByMain)
+		return result
+	}
+
+	cutSet := minimumVertexCut(depGraph.Graph, depGraph.MainModules, target, depGraph.DirectDepList)
+
+	kept := make(map[string][]string, len(depGraph.Graph))
+	for from, tos := range depGraph.Graph {
+		if cutSet[from] {
+			continue
+		}
+		for _, to := range tos {
+			if !cutSet[to] {
+				kept[from] = append(kept[from], to)
+			}
+		}
+	}
+	before := reachableFrom(depGraph.MainModules, depGraph.Graph)
+	after := reachableFrom(depGraph.MainModules, kept)
+	removed := map[string]bool{}
+	for m := range before {
+		if !after[m] {
+			removed[m] = true
+			result.Removed = append(result.Removed, m)
+		}
+	}
+	sort.Strings(result.Removed)
+
+	// Restricted to removed modules, the modules reachable from a cut
+	// module are the ones that disappear with it.
+	removedGraph := map[string][]string{}
+	for from := range removed {
+		for _, to := range depGraph.Graph[from] {
+			if removed[to] && !cutSet[to] {
+				removedGraph[from] = append(removedGraph[from], to)
+			}
+		}
+	}
+	for _, m := range sortedKeys(cutSet) {
+		c := CutCandidate{
+			Module:      m,
+			Direct:      contains(depGraph.DirectDepList, m),
+			RequiredBy:  []string{},
+			AlsoRemoved: []string{},
+		}
+		for from, tos := range depGraph.Graph {
+			if before[from] && contains(tos, m) {
+				c.RequiredBy = append(c.RequiredBy, from)
+			}
+		}
+		sort.Strings(c.RequiredBy)
+		for _, r := range sortedKeys(reachableFrom([]string{m}, removedGraph)) {
+			if r != m && r != target {
+				c.AlsoRemoved = append(c.AlsoRemoved, r)
+			}
+		}
+		result.Cut = append(result.Cut, c)
+	}
+	return result
+}
+
+// minimumVertexCut returns a minimum set of modules, other than the sources
+// and target, whose removal leaves target unreachable from sources. It runs
+// Edmonds-Karp on the graph with every module split into an in and an out
+// node joined by an edge of the module's cost. Every module costs n+1, plus
+// one unless it is in preferred, so the fewest modules are cut first and
+// preferred modules break ties. target must not be a successor of a source.
+func minimumVertexCut(graph map[string][]string, sources []string, target string, preferred []string) map[string]bool {
+	// Only modules on some path from a source to target matter.
+	nodes := sortedKeys(modulesBetween(graph, sources, target))
+	index := make(map[string]int, len(nodes))
+	for i, m := range nodes {
+		index[m] = i
+	}
+
+	n := len(nodes)
+	base := n + 1
+	infinite := base * (n + 2)
+	source := 2 * n
+	in := func(i int) int { return 2 * i }
+	out := func(i int) int { return 2*i + 1 }
+
+	capacity := map[[2]int]int{}
+	adjacent := make([][]int, 2*n+1)
+	addEdge := func(u, v, c int) {
+		if _, ok := capacity[[2]int{u, v}]; !ok {
+			adjacent[u] = append(adjacent[u], v)
+		}
+		if _, ok := capacity[[2]int{v, u}]; !ok {
+			adjacent[v] = append(adjacent[v], u)
+			capacity[[2]int{v, u}] = 0
+		}
+		capacity[[2]int{u, v}] += c
+	}
+	for i, m := range nodes {
+		c := base
+		if !contains(preferred, m) {
+			c++
+		}
+		if m == target || contains(sources, m) {
+			c = infinite
+		}
+		addEdge(in(i), out(i), c)
+		for _, to := range graph[m] {
+			if j, ok := index[to]; ok {
+				addEdge(out(i), in(j), infinite)
+			}
+		}
+	}
+	for _, s := range sources {
+		if i, ok := index[s]; ok {
+			addEdge(source, in(i), infinite)
+		}
+	}
+	sink := in(index[target])
+
+	// residualReach returns the nodes reachable from source in the residual
+	// network, with the BFS parent of each.
+	residualReach := func() map[int]int {
+		parent := map[int]int{source: -1}
+		queue := []int{source}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+			for _, v := range adjacent[u] {
+				if _, seen := parent[v]; seen || capacity[[2]int{u, v}] <= 0 {
+					continue
+				}
+				parent[v] = u
+				queue = append(queue, v)
+			}
+		}
+		return parent
+	}
+	for {
+		parent := residualReach()
+		if _, ok := parent[sink]; !ok {
+			break
+		}
+		flow := infinite
+		for v := sink; v != source; v = parent[v] {
+			if c := capacity[[2]int{parent[v], v}]; c < flow {
+				flow = c
+			}
+		}
+		for v := sink; v != source; v = parent[v] {
+			capacity[[2]int{parent[v], v}] -= flow
+			capacity[[2]int{v, parent[v]}] += flow
+		}
+	}
+
+	reached := residualReach()
+	cut := map[string]bool{}
+	for i, m := range nodes {
+		_, inReached := reached[in(i)]
+		_, outReached := reached[out(i)]
+		if inReached && !outReached {
+			cut[m] = true
+		}
+	}
+	return cut
+}
+
+// sortedKeys returns the keys of set in order.
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// runWhyCut prints the cut of every target. A single plain module prints a
+// WhyCut, several modules or patterns a WhyCutReport.
+func runWhyCut(depGraph *DependencyOverview, args []string, allDeps []string) error {
+	if len(args) == 1 && !isModulePattern(args[0]) {
+		result := findModuleCut(depGraph, allDeps, args[0])
+		if jsonOutput {
+			return outputWhyCutJSON(result)
+		}
+		printWhyCut(result)
+		return nil
+	}
+
+	list := expandWhyTargets(args, allDeps)
+	report := WhyCutReport{
+		MainModules: depGraph.MainModules,
+		Targets:     make(map[string]WhyCut, len(list.Targets)),
+		Unmatched:   list.Unmatched,
+	}
+	for _, target := range list.Targets {
+		report.Targets[target] = findModuleCut(depGraph, allDeps, target)
+	}
+	if jsonOutput {
+		return outputWhyCutJSON(report)
+	}
+	if len(list.Unmatched) > 0 {
+		fmt.Printf("No modules match: %s\n", strings.Join(list.Unmatched, ", "))
+	}
+	for i, target := range list.Targets {
+		if i > 0 || len(list.Unmatched) > 0 {
+			fmt.Println()
+		}
+		printWhyCut(report.Targets[target])
+	}
+	return nil
+}
+
+func outputWhyCutJSON(v interface{}) error {
+	out, err := json.MarshalIndent(v, "", "\t")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(out))
+	return nil
+}
+
+func printWhyCut(result WhyCut) {
+	fmt.Printf("What would remove %s?\n", result.Target)
+	fmt.Println(strings.Repeat("=", 50))
+	fmt.Println()
+
+	switch {
+	case !result.Found:
+		fmt.Println("Not found in dependency graph.")
+		return
+	case contains(result.MainModules, result.Target):
+		fmt.Println("It is a main module.")
+		return
+	case len(result.RequiredByMain) > 0:
+		fmt.Printf("Required directly by main module(s): %s\n", strings.Join(result.RequiredByMain, ", "))
+		fmt.Println("Drop the requirement there; no other module can be cut instead.")
+		return
+	}
+
+	fmt.Printf("Minimum cut (%d modules):\n", len(result.Cut))
+	for _, c := range result.Cut {
+		marker := ""
+		if c.Direct {
+			marker = " [DIRECT]"
+		}
+		fmt.Printf("  - %s%s\n", c.Module, marker)
+		fmt.Printf("      required by: %s\n", strings.Join(c.RequiredBy, ", "))
+		if len(c.AlsoRemoved) > 0 {
+			fmt.Printf("      also removes: %s\n", strings.Join(c.AlsoRemoved, ", "))
+		}
+	}
+	fmt.Println()
+	fmt.Printf("Removing them drops %d modules from the graph, including %s.\n", len(result.Removed), result.Target)
+}
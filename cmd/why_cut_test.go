@@ -0,0 +1,74 @@
+package cmd
+
+import "testing"
+
+func TestFindModuleCutPrefersDirectDependencies(t *testing.T) {
+	depGraph := &DependencyOverview{
+		Graph: map[string][]string{
+			"M": {"A", "B"},
+			"A": {"C", "D"},
+			"B": {"C"},
+			"C": {"X"},
+			"D": {"X"},
+		},
+		DirectDepList: []string{"A", "B"},
+		TransDepList:  []string{"C", "D", "X"},
+		MainModules:   []string{"M"},
+	}
+	allDeps := getAllDeps(depGraph.DirectDepList, depGraph.TransDepList)
+
+	// {A, B} and {C, D} both have two modules; the direct dependencies win.
+	result := findModuleCut(depGraph, allDeps, "X")
+	if len(result.Cut) != 2 || result.Cut[0].Module != "A" || result.Cut[1].Module != "B" {
+		t.Fatalf("expected cut [A B], got %+v", result.Cut)
+	}
+	if !result.Cut[0].Direct || !isSliceSame(result.Cut[0].RequiredBy, []string{"M"}) {
+		t.Fatalf("unexpected candidate A: %+v", result.Cut[0])
+	}
+	if !isSliceSame(result.Cut[0].AlsoRemoved, []string{"C", "D"}) {
+		t.Fatalf("expected A to also remove C and D, got %v", result.Cut[0].AlsoRemoved)
+	}
+	if !isSliceSame(result.Removed, []string{"A", "B", "C", "D", "X"}) {
+		t.Fatalf("unexpected removed modules: %v", result.Removed)
+	}
+}
+
+func TestFindModuleCutPrefersFewerModules(t *testing.T) {
+	depGraph := &DependencyOverview{
+		Graph: map[string][]string{
+			"M": {"A", "F"},
+			"A": {"B"},
+			"F": {"B"},
+			"B": {"X"},
+		},
+		DirectDepList: []string{"A", "F"},
+		TransDepList:  []string{"B", "X"},
+		MainModules:   []string{"M"},
+	}
+	allDeps := getAllDeps(depGraph.DirectDepList, depGraph.TransDepList)
+
+	result := findModuleCut(depGraph, allDeps, "X")
+	if len(result.Cut) != 1 || result.Cut[0].Module != "B" || result.Cut[0].Direct {
+		t.Fatalf("expected cut [B], got %+v", result.Cut)
+	}
+	if !isSliceSame(result.Cut[0].RequiredBy, []string{"A", "F"}) {
+		t.Fatalf("unexpected requirers of B: %v", result.Cut[0].RequiredBy)
+	}
+	if !isSliceSame(result.Removed, []string{"B", "X"}) {
+		t.Fatalf("unexpected removed modules: %v", result.Removed)
+	}
+}
+
+func TestFindModuleCutRequiredByMain(t *testing.T) {
+	depGraph := &DependencyOverview{
+		Graph:         map[string][]string{"M": {"A", "X"}, "A": {"X"}},
+		DirectDepList: []string{"A", "X"},
+		MainModules:   []string{"M"},
+	}
+	allDeps := getAllDeps(depGraph.DirectDepList, depGraph.TransDepList)
+
+	result := findModuleCut(depGraph, allDeps, "X")
+	if !isSliceSame(result.RequiredByMain, []string{"M"}) || len(result.Cut) != 0 {
+		t.Fatalf("expected X to be required by M without a cut, got %+v", result)
+	}
+}
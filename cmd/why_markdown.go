@@ -0,0 +1,185 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var whyMarkdown bool
+
+// whyMarkdownBudget keeps why --markdown below GitHub's limit of 65536
+// characters per comment, leaving room for text a bot adds around it.
+const whyMarkdownBudget = 60000
+
+// whyMarkdownDetail is how much of every result --markdown shows.
+type whyMarkdownDetail struct {
+	Paths      int
+	Dependents int
+}
+
+// whyMarkdownDetails are tried in order until the report fits the budget.
+// The last level leaves out the paths entirely.
+var whyMarkdownDetails = []whyMarkdownDetail{
+	{Paths: whyDefaultTextPaths, Dependents: 50},
+	{Paths: 10, Dependents: 20},
+	{Paths: 5, Dependents: 10},
+	{Paths: 1, Dependents: 5},
+	{Paths: 0, Dependents: 0},
+}
+
+// renderWhyMarkdown renders results as GitHub-flavoured Markdown of at most
+// budget bytes. When the full report is too large it shows fewer paths and
+// dependents per module, and as a last resort leaves out whole modules.
+func renderWhyMarkdown(results []WhyResult, unmatched []string, budget int) string {
+	for i, detail := range whyMarkdownDetails {
+		if out := writeWhyMarkdown(results, unmatched, detail, i > 0, 0); len(out) <= budget {
+			return out
+		}
+	}
+	least := whyMarkdownDetails[len(whyMarkdownDetails)-1]
+	for n := len(results) - 1; n > 0; n-- {
+		if out := writeWhyMarkdown(results[:n], unmatched, least, true, len(results)-n); len(out) <= budget {
+			return out
+		}
+	}
+	return writeWhyMarkdown(nil, unmatched, least, true, len(results))
+}
+
+// writeWhyMarkdown renders results at the given detail. shortened adds a
+// note that the report was cut down, and omitted counts the modules left
+// out.
+func writeWhyMarkdown(results []WhyResult, unmatched []string, detail whyMarkdownDetail, shortened bool, omitted int) string {
+	var b strings.Builder
+	if len(results)+omitted != 1 || len(unmatched) > 0 {
+		found := 0
+		for _, r := range results {
+			if r.Found {
+				found++
+			}
+		}
+		fmt.Fprintf(&b, "## Why are these modules included?\n\n")
+		fmt.Fprintf(&b, "Explaining %d modules (%d found in the dependency graph).\n\n", len(results)+omitted, found)
+		if len(unmatched) > 0 {
+			fmt.Fprintf(&b, "No modules match %s.\n\n", markdownCodeList(unmatched))
+		}
+	}
+	if shortened {
+		b.WriteString("_Shortened to fit in a GitHub comment; run `depstat why` locally for the full report._\n\n")
+	}
+	for _, r := range results {
+		writeWhyMarkdownResult(&b, r, detail)
+	}
+	if omitted > 0 {
+		fmt.Fprintf(&b, "_%d more modules left out to fit in a GitHub comment._\n", omitted)
+	}
+	return b.String()
+}
+
+func writeWhyMarkdownResult(b *strings.Builder, r WhyResult, detail whyMarkdownDetail) {
+	fmt.Fprintf(b, "### Why is `%s` included?\n\n", r.Target)
+	if !r.Found {
+		b.WriteString("Not found in the dependency graph.\n\n")
+		return
+	}
+
+	total := strconv.Itoa(r.TotalPaths)
+	if r.TotalPathsLowerBound {
+		total = "at least " + total
+	}
+	fmt.Fprintf(b, "%s dependency paths, %d direct dependents.\n\n", total, len(r.DirectDeps))
+	if r.Truncated {
+		fmt.Fprintf(b, "> **Note:** only the %d shortest paths were kept (`--max-paths`).\n\n", len(r.Paths))
+	}
+
+	if detail.Paths > 0 && len(r.Paths) > 0 {
+		paths := r.Paths
+		if len(paths) > detail.Paths {
+			paths = paths[:detail.Paths]
+		}
+		fmt.Fprintf(b, "<details>\n<summary>Shortest paths (showing %d of %s)</summary>\n\n", len(paths), total)
+		writeMarkdownPathTree(b, paths, r.Target)
+		b.WriteString("\n</details>\n\n")
+	}
+
+	if detail.Dependents > 0 && len(r.DirectDeps) > 0 {
+		b.WriteString("| Direct dependent | Main module |\n| --- | --- |\n")
+		for i, dep := range r.DirectDeps {
+			if i == detail.Dependents {
+				fmt.Fprintf(b, "| _and %d more_ | |\n", len(r.DirectDeps)-i)
+				break
+			}
+			main := ""
+			if contains(r.MainModules, dep) {
+				main = "yes"
+			}
+			fmt.Fprintf(b, "| `%s` | %s |\n", dep, main)
+		}
+		b.WriteString("\n")
+	}
+}
+
+// writeMarkdownPathTree renders paths as nested lists, merging common
+// prefixes. Paths are expected shortest first, which keeps the tree in the
+// same order.
+func writeMarkdownPathTree(b *strings.Builder, paths []WhyPath, target string) {
+	type treeNode struct {
+		module   string
+		children []*treeNode
+	}
+	root := &treeNode{}
+	for _, wp := range paths {
+		node := root
+		for _, m := range wp.Path {
+			var next *treeNode
+			for _, c := range node.children {
+				if c.module == m {
+					next = c
+					break
+				}
+			}
+			if next == nil {
+				next = &treeNode{module: m}
+				node.children = append(node.children, next)
+			}
+			node = next
+		}
+	}
+	var write func(node *treeNode, depth int)
+	write = func(node *treeNode, depth int) {
+		for _, c := range node.children {
+			label := "`" + c.module + "`"
+			if c.module == target {
+				label = "**" + label + "**"
+			}
+			fmt.Fprintf(b, "%s- %s\n", strings.Repeat("  ", depth), label)
+			write(c, depth+1)
+		}
+	}
+	write(root, 0)
+}
+
+// markdownCodeList formats items as a comma-separated list of code spans.
+func markdownCodeList(items []string) string {
+	quoted := make([]string, 0, len(items))
+	for _, item := range items {
+		quoted = append(quoted, "`"+item+"`")
+	}
+	return strings.Join(quoted, ", ")
+}
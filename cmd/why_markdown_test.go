@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRenderWhyMarkdown(t *testing.T) {
+	result := WhyResult{
+		Target:      "X",
+		Found:       true,
+		MainModules: []string{"M"},
+		Paths: []WhyPath{
+			{Path: []string{"M", "X"}, Direct: true},
+			{Path: []string{"M", "A", "X"}},
+		},
+		DirectDeps: []string{"A", "M"},
+		Truncated:  true,
+		TotalPaths: 5,
+	}
+
+	out := renderWhyMarkdown([]WhyResult{result}, nil, whyMarkdownBudget)
+	for _, want := range []string{
+		"### Why is `X` included?",
+		"5 dependency paths, 2 direct dependents.",
+		"> **Note:** only the 2 shortest paths were kept",
+		"<summary>Shortest paths (showing 2 of 5)</summary>",
+		"- `M`\n  - **`X`**\n  - `A`\n    - **`X`**\n",
+		"| `M` | yes |",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected %q in output:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "Shortened") || strings.Contains(out, "## Why are these") {
+		t.Fatalf("unexpected summary or header for a single small result:\n%s", out)
+	}
+}
+
+func TestRenderWhyMarkdownFitsBudget(t *testing.T) {
+	var results []WhyResult
+	for i := 0; i < 50; i++ {
+		r := WhyResult{Target: fmt.Sprintf("example.com/target%d", i), Found: true, MainModules: []string{"M"}}
+		for j := 0; j < 30; j++ {
+			dep := fmt.Sprintf("example.com/some/rather/long/module/path/dependent%d", j)
+			r.DirectDeps = append(r.DirectDeps, dep)
+			r.Paths = append(r.Paths, WhyPath{Path: []string{"M", dep, r.Target}})
+		}
+		r.TotalPaths = len(r.Paths)
+		results = append(results, r)
+	}
+
+	full := writeWhyMarkdown(results, nil, whyMarkdownDetails[0], false, 0)
+	const budget = 20000
+	if len(full) <= budget {
+		t.Fatalf("test results should exceed the budget, got %d bytes", len(full))
+	}
+	out := renderWhyMarkdown(results, nil, budget)
+	if len(out) > budget {
+		t.Fatalf("expected at most %d bytes, got %d", budget, len(out))
+	}
+	if !strings.Contains(out, "_Shortened to fit in a GitHub comment") {
+		t.Fatalf("expected a shortened note:\n%s", out)
+	}
+
+	// With a tiny budget whole modules are left out.
+	out = renderWhyMarkdown(results, nil, 2000)
+	if len(out) > 2000 || !strings.Contains(out, "more modules left out") {
+		t.Fatalf("expected modules to be left out within 2000 bytes, got %d bytes:\n%s", len(out), out)
+	}
+}
@@ -0,0 +1,195 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+var whyPackages bool
+
+// PackageChain is the import chain behind a module path: each package
+// imports the next one, starting in a main module and ending in the target
+// module. Packages use the `go list` import paths, so test variants read
+// "pkg [pkg.test]". Test is set when the chain goes through a test package.
+type PackageChain struct {
+	Packages []string `json:"packages"`
+	Test     bool     `json:"test,omitempty"`
+}
+
+// listedPackage is the part of `go list -json` output used by why --packages.
+type listedPackage struct {
+	ImportPath string
+	Module     *struct {
+		Path string
+	}
+	Imports []string
+}
+
+// packageGraph is the import graph of the packages built for the main
+// modules, including their tests.
+type packageGraph struct {
+	Imports  map[string][]string
+	ModuleOf map[string]string
+}
+
+// loadPackageGraph runs `go list -e -deps -test -json` in the configured
+// directory on the packages of every main module.
+func loadPackageGraph(mainModules []string) (*packageGraph, error) {
+	args := []string{"list", "-e", "-deps", "-test", "-json"}
+	for _, m := range mainModules {
+		args = append(args, m+"/...")
+	}
+	goListCmd := exec.Command("go", args...)
+	if dir != "" {
+		goListCmd.Dir = dir
+	}
+	var stdout, stderr bytes.Buffer
+	goListCmd.Stdout = &stdout
+	goListCmd.Stderr = &stderr
+	if err := goListCmd.Run(); err != nil {
+		return nil, fmt.Errorf("go list -deps -test failed: %v: %s", err, stderr.String())
+	}
+	return parsePackageList(&stdout)
+}
+
+// parsePackageList parses a stream of `go list -json` package objects.
+// Packages outside any module (the standard library, generated test mains)
+// are left out.
+func parsePackageList(r io.Reader) (*packageGraph, error) {
+	g := &packageGraph{Imports: map[string][]string{}, ModuleOf: map[string]string{}}
+	dec := json.NewDecoder(r)
+	for {
+		var pkg listedPackage
+		if err := dec.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("parsing go list output: %v", err)
+		}
+		if pkg.Module == nil {
+			continue
+		}
+		g.ModuleOf[pkg.ImportPath] = pkg.Module.Path
+		g.Imports[pkg.ImportPath] = pkg.Imports
+	}
+	return g, nil
+}
+
+// isTestPackage reports whether importPath is a test variant
+// ("pkg [pkg.test]"), an external test package ("pkg_test") or a test main
+// ("pkg.test").
+func isTestPackage(importPath string) bool {
+	name := strings.SplitN(importPath, " ", 2)[0]
+	return strings.Contains(importPath, " [") || strings.HasSuffix(name, "_test") || strings.HasSuffix(name, ".test")
+}
+
+// importChain returns the shortest import chain from a package of the first
+// module of modulePath to a package of its last module that follows the
+// modules of modulePath in order: every import stays in the module of the
+// importing package or moves on to the next module of the path. Chains
+// without test packages are preferred. It returns nil if the modules are
+// only linked by requirements.
+func (g *packageGraph) importChain(modulePath []string) *PackageChain {
+	if len(modulePath) == 0 {
+		return nil
+	}
+	// step is a package together with the position of its module in
+	// modulePath.
+	type step struct {
+		pkg   string
+		index int
+	}
+	last := len(modulePath) - 1
+
+	var starts []string
+	for pkg, mod := range g.ModuleOf {
+		if mod == modulePath[0] {
+			starts = append(starts, pkg)
+		}
+	}
+	sort.Strings(starts)
+
+	for _, withTests := range []bool{false, true} {
+		parent := map[step]step{}
+		root := step{index: -1}
+		var queue []step
+		for _, pkg := range starts {
+			if withTests || !isTestPackage(pkg) {
+				s := step{pkg: pkg}
+				parent[s] = root
+				queue = append(queue, s)
+			}
+		}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			if current.index == last {
+				var chain []string
+				test := false
+				for s := current; s != root; s = parent[s] {
+					chain = append([]string{s.pkg}, chain...)
+					test = test || isTestPackage(s.pkg)
+				}
+				return &PackageChain{Packages: chain, Test: test}
+			}
+			for _, pkg := range g.Imports[current.pkg] {
+				next := step{pkg: pkg, index: current.index}
+				if g.ModuleOf[pkg] == modulePath[current.index+1] {
+					next.index++
+				} else if g.ModuleOf[pkg] != modulePath[current.index] {
+					continue
+				}
+				if _, seen := parent[next]; seen {
+					continue
+				}
+				if !withTests && isTestPackage(pkg) {
+					continue
+				}
+				parent[next] = current
+				queue = append(queue, next)
+			}
+		}
+	}
+	return nil
+}
+
+// addPackageChains fills in the package import chain of every path in
+// result.
+func addPackageChains(result *WhyResult, g *packageGraph) {
+	for i := range result.Paths {
+		result.Paths[i].Packages = g.importChain(result.Paths[i].Path)
+	}
+}
+
+// printPackageChain prints the import chain below a module path.
+func printPackageChain(chain *PackageChain) {
+	if chain == nil {
+		fmt.Println("       packages: no import chain (module requirement only)")
+		return
+	}
+	marker := ""
+	if chain.Test {
+		marker = " [test]"
+	}
+	fmt.Printf("       packages%s: %s\n", marker, strings.Join(chain.Packages, " -> "))
+}
@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+const testPackageList = `{"ImportPath": "example.com/main/cmd", "Module": {"Path": "example.com/main"}, "Imports": ["example.com/lib/api", "fmt"]}
+{"ImportPath": "fmt", "Standard": true}
+{"ImportPath": "example.com/lib/api", "Module": {"Path": "example.com/lib"}, "Imports": ["example.com/target/core"]}
+{"ImportPath": "example.com/target/core", "Module": {"Path": "example.com/target"}}
+{"ImportPath": "example.com/main/pkg [example.com/main/pkg.test]", "Module": {"Path": "example.com/main"}, "Imports": ["example.com/testlib/assert"]}
+{"ImportPath": "example.com/testlib/assert", "Module": {"Path": "example.com/testlib"}, "Imports": ["example.com/other/x"]}
+{"ImportPath": "example.com/other/x", "Module": {"Path": "example.com/other"}}
+{"ImportPath": "example.com/main/pkg.test", "Imports": ["example.com/main/pkg [example.com/main/pkg.test]"]}
+`
+
+func TestParsePackageList(t *testing.T) {
+	g, err := parsePackageList(strings.NewReader(testPackageList))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := g.ModuleOf["fmt"]; ok {
+		t.Fatal("standard library packages must be skipped")
+	}
+	if _, ok := g.ModuleOf["example.com/main/pkg.test"]; ok {
+		t.Fatal("test mains without a module must be skipped")
+	}
+	if g.ModuleOf["example.com/lib/api"] != "example.com/lib" {
+		t.Fatalf("unexpected module of lib/api: %q", g.ModuleOf["example.com/lib/api"])
+	}
+}
+
+func TestImportChain(t *testing.T) {
+	g, err := parsePackageList(strings.NewReader(testPackageList))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := g.importChain([]string{"example.com/main", "example.com/lib", "example.com/target"})
+	if chain == nil || chain.Test {
+		t.Fatalf("expected a non-test chain, got %+v", chain)
+	}
+	if want := []string{"example.com/main/cmd", "example.com/lib/api", "example.com/target/core"}; !isSliceSame(chain.Packages, want) {
+		t.Fatalf("got %v, want %v", chain.Packages, want)
+	}
+
+	// The requirement path skips lib, so there is no import chain.
+	if chain := g.importChain([]string{"example.com/main", "example.com/target"}); chain != nil {
+		t.Fatalf("expected no chain, got %+v", chain)
+	}
+
+	chain = g.importChain([]string{"example.com/main", "example.com/testlib", "example.com/other"})
+	if chain == nil || !chain.Test {
+		t.Fatalf("expected a chain through test packages, got %+v", chain)
+	}
+	if chain.Packages[0] != "example.com/main/pkg [example.com/main/pkg.test]" {
+		t.Fatalf("unexpected chain start: %v", chain.Packages)
+	}
+}
+
+func TestIsTestPackage(t *testing.T) {
+	for pkg, want := range map[string]bool{
+		"example.com/a":                           false,
+		"example.com/a [example.com/a.test]":      true,
+		"example.com/a_test [example.com/a.test]": true,
+		"example.com/a.test":                      true,
+	} {
+		if got := isTestPackage(pkg); got != want {
+			t.Errorf("isTestPackage(%q) = %v, want %v", pkg, got, want)
+		}
+	}
+}
+
+func TestImportChainFollowsModulePath(t *testing.T) {
+	// main imports target both directly and through lib.
+	g, err := parsePackageList(strings.NewReader(`{"ImportPath": "example.com/main/a", "Module": {"Path": "example.com/main"}, "Imports": ["example.com/target/core"]}
+{"ImportPath": "example.com/main/c", "Module": {"Path": "example.com/main"}, "Imports": ["example.com/lib/api"]}
+{"ImportPath": "example.com/lib/api", "Module": {"Path": "example.com/lib"}, "Imports": ["example.com/target/core"]}
+{"ImportPath": "example.com/target/core", "Module": {"Path": "example.com/target"}}
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain := g.importChain([]string{"example.com/main", "example.com/lib", "example.com/target"})
+	want := []string{"example.com/main/c", "example.com/lib/api", "example.com/target/core"}
+	if chain == nil || !isSliceSame(chain.Packages, want) {
+		t.Fatalf("got %+v, want %v", chain, want)
+	}
+	chain = g.importChain([]string{"example.com/main", "example.com/target"})
+	want = []string{"example.com/main/a", "example.com/target/core"}
+	if chain == nil || !isSliceSame(chain.Packages, want) {
+		t.Fatalf("got %+v, want %v", chain, want)
+	}
+
+	// lib does not import target back, so the chain cannot go main -> target
+	// -> lib.
+	if chain := g.importChain([]string{"example.com/main", "example.com/target", "example.com/lib"}); chain != nil {
+		t.Fatalf("expected no chain, got %+v", chain)
+	}
+}
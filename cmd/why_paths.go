@@ -0,0 +1,225 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// whyVirtualSource is the node kShortestPaths links to every source, so that
+// paths from several main modules are ranked together.
+const whyVirtualSource = "\x00source"
+
+// modulesBetween returns the modules that lie on some path from sources to
+// target.
+func modulesBetween(graph map[string][]string, sources []string, target string) map[string]bool {
+	reverse := map[string][]string{}
+	for from, tos := range graph {
+		for _, to := range tos {
+			reverse[to] = append(reverse[to], from)
+		}
+	}
+	forward := reachableFrom(sources, graph)
+	backward := reachableFrom([]string{target}, reverse)
+	between := map[string]bool{}
+	for m := range forward {
+		if backward[m] {
+			between[m] = true
+		}
+	}
+	return between
+}
+
+// restrictGraph returns the edges of graph between modules of keep.
+func restrictGraph(graph map[string][]string, keep map[string]bool) map[string][]string {
+	restricted := make(map[string][]string, len(keep))
+	for from, tos := range graph {
+		if !keep[from] {
+			continue
+		}
+		for _, to := range tos {
+			if keep[to] {
+				restricted[from] = append(restricted[from], to)
+			}
+		}
+	}
+	return restricted
+}
+
+// kShortestPaths returns the k shortest simple paths from any of sources to
+// target, shortest first, using Yen's algorithm. Paths of the same length
+// are ordered by module path. k <= 0 returns every path.
+func kShortestPaths(graph map[string][]string, sources []string, target string, k int) [][]string {
+	sub := restrictGraph(graph, modulesBetween(graph, sources, target))
+	sub[whyVirtualSource] = append([]string{}, sources...)
+
+	first := shortestPath(sub, whyVirtualSource, target, nil, nil)
+	if first == nil {
+		return nil
+	}
+	found := [][]string{first}
+	seen := map[string]bool{strings.Join(first, "\x00"): true}
+	var candidates [][]string
+	for k <= 0 || len(found) < k {
+		prev := found[len(found)-1]
+		for i := 0; i+1 < len(prev); i++ {
+			root := prev[:i+1]
+			// Leave every known path with this root by a different edge.
+			blockedEdges := map[[2]string]bool{}
+			for _, p := range found {
+				if len(p) > i+1 && isSliceSame(p[:i+1], root) {
+					blockedEdges[[2]string{p[i], p[i+1]}] = true
+				}
+			}
+			blockedNodes := map[string]bool{}
+			for _, m := range root[:i] {
+				blockedNodes[m] = true
+			}
+			spur := shortestPath(sub, prev[i], target, blockedNodes, blockedEdges)
+			if spur == nil {
+				continue
+			}
+			path := append(append([]string{}, root[:i]...), spur...)
+			key := strings.Join(path, "\x00")
+			if !seen[key] {
+				seen[key] = true
+				candidates = append(candidates, path)
+			}
+		}
+		if len(candidates) == 0 {
+			break
+		}
+		best := 0
+		for i, c := range candidates {
+			if pathLess(c, candidates[best]) {
+				best = i
+			}
+		}
+		found = append(found, candidates[best])
+		candidates = append(candidates[:best], candidates[best+1:]...)
+	}
+
+	paths := make([][]string, 0, len(found))
+	for _, p := range found {
+		paths = append(paths, p[1:])
+	}
+	sort.SliceStable(paths, func(i, j int) bool { return pathLess(paths[i], paths[j]) })
+	return paths
+}
+
+// pathLess orders paths by length, then by module path.
+func pathLess(a, b []string) bool {
+	if len(a) != len(b) {
+		return len(a) < len(b)
+	}
+	return strings.Join(a, " -> ") < strings.Join(b, " -> ")
+}
+
+// shortestPath returns a shortest path from start to target by breadth-first
+// search, avoiding blockedNodes and blockedEdges, or nil if there is none.
+func shortestPath(graph map[string][]string, start, target string, blockedNodes map[string]bool, blockedEdges map[[2]string]bool) []string {
+	parent := map[string]string{start: ""}
+	queue := []string{start}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if current == target {
+			var path []string
+			for m := current; m != ""; m = parent[m] {
+				path = append([]string{m}, path...)
+			}
+			return path
+		}
+		for _, next := range graph[current] {
+			if _, seen := parent[next]; seen || blockedNodes[next] || blockedEdges[[2]string{current, next}] {
+				continue
+			}
+			parent[next] = current
+			queue = append(queue, next)
+		}
+	}
+	return nil
+}
+
+// countPaths counts the paths from sources to target without enumerating
+// them, by dynamic programming over the condensation of the modules between
+// them. If those modules form a DAG the count is exact. Otherwise each path
+// of the condensation stands for at least one simple path, so the count is a
+// lower bound and exact is false. Counts that overflow an int are capped at
+// math.MaxInt, with exact false.
+func countPaths(graph map[string][]string, sources []string, target string) (count int, exact bool) {
+	between := modulesBetween(graph, sources, target)
+	if !between[target] {
+		return 0, true
+	}
+	sub := restrictGraph(graph, between)
+	for m := range between {
+		if _, ok := sub[m]; !ok {
+			sub[m] = nil
+		}
+	}
+
+	exact = true
+	componentOf := map[string]int{}
+	components := stronglyConnectedComponents(sub)
+	for i, component := range components {
+		for _, m := range component {
+			componentOf[m] = i
+		}
+		if len(component) > 1 || contains(sub[component[0]], component[0]) {
+			exact = false
+		}
+	}
+
+	// Components come after the ones they depend on, so successors are
+	// counted first.
+	ways := make([]int, len(components))
+	for i, component := range components {
+		if componentOf[target] == i {
+			ways[i] = 1
+			continue
+		}
+		successors := map[int]bool{}
+		for _, m := range component {
+			for _, to := range sub[m] {
+				if j := componentOf[to]; j != i {
+					successors[j] = true
+				}
+			}
+		}
+		for j := range successors {
+			ways[i] = addCapped(ways[i], ways[j], &exact)
+		}
+	}
+	for _, s := range sources {
+		if between[s] {
+			count = addCapped(count, ways[componentOf[s]], &exact)
+		}
+	}
+	return count, exact
+}
+
+// addCapped returns a+b, or math.MaxInt with exact cleared on overflow.
+func addCapped(a, b int, exact *bool) int {
+	if a > math.MaxInt-b {
+		*exact = false
+		return math.MaxInt
+	}
+	return a + b
+}
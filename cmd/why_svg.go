@@ -18,8 +18,6 @@ package cmd
 
 import (
 	"fmt"
-	"math"
-	"sort"
 	"strings"
 )
 
@@ -47,6 +45,9 @@ const (
 	svgCharWidth    = 6.8
 	svgMinWidth     = 500.0
 	svgMaxWidth     = 2400.0
+	// svgUpwardEdgeBend is the horizontal offset of the control point of
+	// edges pointing upwards.
+	svgUpwardEdgeBend = 60.0
 )
 
 func outputWhySVG(result WhyResult) error {
@@ -57,225 +58,75 @@ func outputWhySVG(result WhyResult) error {
 `, xmlEscape(result.Target))
 		return nil
 	}
-
-	// Extract unique nodes and edges from paths
-	nodeSet := make(map[string]bool)
-	edgeSet := make(map[svgEdge]bool)
-	for _, wp := range result.Paths {
-		for i, node := range wp.Path {
-			nodeSet[node] = true
-			if i > 0 {
-				edgeSet[svgEdge{From: wp.Path[i-1], To: node}] = true
-			}
-		}
-	}
-
-	// Assign layers via BFS using longest path from root
-	layerOf := assignLayers(nodeSet, edgeSet, result)
-
-	// Group nodes by layer
-	numLayers := 0
-	layerNodes := make(map[int][]string)
-	for node, l := range layerOf {
-		layerNodes[l] = append(layerNodes[l], node)
-		if l+1 > numLayers {
-			numLayers = l + 1
-		}
-	}
-	for l := range layerNodes {
-		sort.Strings(layerNodes[l])
-	}
-
-	// Compute node labels and widths
-	labels := make(map[string]string)
-	widths := make(map[string]float64)
-	for node := range nodeSet {
-		label := abbreviateModule(node, result.MainModules)
-		labels[node] = label
-		w := math.Max(svgMinNodeWidth, float64(len(label))*svgCharWidth+24)
-		widths[node] = w
-	}
-
-	// Find the widest layer to set SVG width
-	maxLayerWidth := 0.0
-	for l := 0; l < numLayers; l++ {
-		var tw float64
-		for _, node := range layerNodes[l] {
-			tw += widths[node]
-		}
-		tw += float64(len(layerNodes[l])-1) * svgNodeSpacing
-		if tw > maxLayerWidth {
-			maxLayerWidth = tw
-		}
-	}
-	svgWidth := math.Max(svgMinWidth, math.Min(svgMaxWidth, maxLayerWidth+2*svgPaddingX))
-	svgHeight := svgPaddingTop + float64(numLayers-1)*svgLayerSpacing + svgNodeHeight + 40
-
-	// Compute positions (centered per layer)
-	positions := make(map[string]nodePos)
-	for l := 0; l < numLayers; l++ {
-		nodes := layerNodes[l]
-		var totalW float64
-		for _, n := range nodes {
-			totalW += widths[n]
-		}
-		totalW += float64(len(nodes)-1) * svgNodeSpacing
-		x := (svgWidth - totalW) / 2
-		y := svgPaddingTop + float64(l)*svgLayerSpacing
-		for _, n := range nodes {
-			positions[n] = nodePos{X: x, Y: y, W: widths[n], H: svgNodeHeight}
-			x += widths[n] + svgNodeSpacing
-		}
-	}
-
-	// Build SVG
-	var b strings.Builder
-	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" viewBox="0 0 %.0f %.0f" font-family="system-ui,-apple-system,sans-serif">`, svgWidth, svgHeight, svgWidth, svgHeight)
-	fmt.Fprintln(&b)
-
-	// Defs: arrow markers
-	fmt.Fprint(&b, `<defs>
-  <marker id="a" viewBox="0 0 10 6" refX="10" refY="3" markerWidth="8" markerHeight="5" orient="auto-start-reverse">
-    <path d="M0 0L10 3L0 6z" fill="#888"/>
-  </marker>
-  <marker id="ar" viewBox="0 0 10 6" refX="10" refY="3" markerWidth="8" markerHeight="5" orient="auto-start-reverse">
-    <path d="M0 0L10 3L0 6z" fill="#D32F2F"/>
-  </marker>
-</defs>
-`)
-
-	// Title
-	fmt.Fprintf(&b, `<text x="%.1f" y="28" text-anchor="middle" font-size="14" font-weight="600" fill="#333">Why is %s included?</text>`, svgWidth/2, xmlEscape(result.Target))
-	fmt.Fprintln(&b)
-	fmt.Fprintf(&b, `<text x="%.1f" y="46" text-anchor="middle" font-size="11" fill="#888">%d paths, %d direct dependent(s)</text>`, svgWidth/2, len(result.Paths), len(result.DirectDeps))
-	fmt.Fprintln(&b)
-
-	// Legend
-	renderSVGLegend(&b, 16, 60)
-
-	// Edges (before nodes so nodes draw on top)
-	directDepSet := make(map[string]bool)
-	for _, d := range result.DirectDeps {
-		directDepSet[d] = true
-	}
-
-	for e := range edgeSet {
-		fp := positions[e.From]
-		tp := positions[e.To]
-		path := svgBezierPath(fp, tp)
-
-		isDirectToTarget := e.To == result.Target && directDepSet[e.From]
-		layerDiff := layerOf[e.To] - layerOf[e.From]
-
-		stroke := "#888"
-		sw := "1.3"
-		marker := "url(#a)"
-		dash := ""
-
-		if isDirectToTarget {
-			stroke = "#D32F2F"
-			sw = "2.2"
-			marker = "url(#ar)"
-		} else if layerDiff > 1 {
-			dash = ` stroke-dasharray="5,3"`
-		}
-
-		fmt.Fprintf(&b, `<path d="%s" fill="none" stroke="%s" stroke-width="%s" marker-end="%s"%s/>`, path, stroke, sw, marker, dash)
-		fmt.Fprintln(&b)
-	}
-
-	// Nodes
-	sortedNodes := make([]string, 0, len(nodeSet))
-	for n := range nodeSet {
-		sortedNodes = append(sortedNodes, n)
-	}
-	sort.Strings(sortedNodes)
-
-	for _, node := range sortedNodes {
-		p := positions[node]
-		c := classifyNodeColor(node, result)
-		sw := "1.5"
-		if node == result.Target || contains(result.MainModules, node) {
-			sw = "2"
-		}
-		fmt.Fprintf(&b, `<g><title>%s</title>`, xmlEscape(node))
-		fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" rx="%.0f" fill="%s" stroke="%s" stroke-width="%s"/>`,
-			p.X, p.Y, p.W, p.H, svgCornerRadius, c.Fill, c.Stroke, sw)
-		fmt.Fprintf(&b, `<text x="%.1f" y="%.1f" text-anchor="middle" dominant-baseline="central" font-size="%.0f" fill="%s">%s</text>`,
-			p.X+p.W/2, p.Y+p.H/2, svgFontSize, c.Text, xmlEscape(labels[node]))
-		fmt.Fprintln(&b, `</g>`)
-	}
-
-	// Footer
-	fmt.Fprintf(&b, `<text x="%.1f" y="%.0f" text-anchor="middle" font-size="10" fill="#aaa">generated by depstat</text>`,
-		svgWidth/2, svgHeight-12)
-	fmt.Fprintln(&b)
-
-	fmt.Fprintln(&b, `</svg>`)
-	fmt.Print(b.String())
+	subtitle := fmt.Sprintf("%d paths, %d direct dependent(s)", len(result.Paths), len(result.DirectDeps))
+	fmt.Print(renderWhySVG(fmt.Sprintf("Why is %s included?", result.Target), subtitle, []WhyResult{result}))
 	return nil
 }
 
-// assignLayers does BFS from main modules, using the longest path from root
-// so that the target naturally sinks to the bottom layer.
-func assignLayers(nodeSet map[string]bool, edgeSet map[svgEdge]bool, result WhyResult) map[string]int {
-	layerOf := make(map[string]int)
-
-	// Build adjacency list from edge set
-	adj := make(map[string][]string)
-	for e := range edgeSet {
-		adj[e.From] = append(adj[e.From], e.To)
-	}
-
-	// Initialize roots at layer 0
-	queue := make([]string, 0)
-	for _, m := range result.MainModules {
-		if nodeSet[m] {
-			layerOf[m] = 0
-			queue = append(queue, m)
+// renderWhySVG draws the paths of all results as one diagram with every
+// target highlighted.
+func renderWhySVG(title, subtitle string, results []WhyResult) string {
+	nodes := whyPathModules(results...)
+	var edges []svgEdge
+	for _, e := range whyPathEdges(results...) {
+		edges = append(edges, svgEdge{From: e.From, To: e.To})
+	}
+
+	targets := whyTargets(results)
+	targetList := make([]string, 0, len(results))
+	// directDeps holds the edges from a direct dependent to its target.
+	directDeps := make(map[svgEdge]bool)
+	for _, result := range results {
+		targetList = append(targetList, result.Target)
+		for _, d := range result.DirectDeps {
+			directDeps[svgEdge{From: d, To: result.Target}] = true
 		}
 	}
-
-	// BFS assigning max depth
-	for len(queue) > 0 {
-		cur := queue[0]
-		queue = queue[1:]
-		for _, next := range adj[cur] {
-			if !nodeSet[next] {
-				continue
+	mainMods := results[0].MainModules
+
+	return renderSVGDiagram(svgDiagram{
+		Title:    title,
+		Subtitle: subtitle,
+		Nodes:    nodes,
+		Edges:    edges,
+		// Roots at the top and the targets sink to the bottom layer.
+		Layout: layoutOptions{Roots: mainMods, Sinks: targetList, MaxWidth: svgMaxWidth},
+		Legend: svgDefaultLegend,
+		NodeStyle: func(node string) svgNodeStyle {
+			target := ""
+			if targets[node] {
+				target = node
 			}
-			newLayer := layerOf[cur] + 1
-			if prev, ok := layerOf[next]; !ok || newLayer > prev {
-				layerOf[next] = newLayer
-				queue = append(queue, next)
+			s := svgNodeStyle{
+				Label: abbreviateModule(node, mainMods),
+				Color: classifyNodeColor(node, target, mainMods),
 			}
-		}
-	}
-
-	// Ensure target is at the bottom
-	maxLayer := 0
-	for _, l := range layerOf {
-		if l > maxLayer {
-			maxLayer = l
-		}
-	}
-	if layerOf[result.Target] < maxLayer {
-		layerOf[result.Target] = maxLayer
-	}
-
-	return layerOf
+			if targets[node] || contains(mainMods, node) {
+				s.StrokeWidth = "2"
+			}
+			return s
+		},
+		EdgeStyle: func(e svgEdge, layerDiff int) svgEdgeStyle {
+			if directDeps[e] {
+				return svgEdgeStyle{Stroke: "#D32F2F", Width: "2.2"}
+			}
+			return svgEdgeStyle{Dashed: layerDiff > 1}
+		},
+	})
 }
 
-func classifyNodeColor(node string, result WhyResult) nodeColor {
-	if node == result.Target {
+// classifyNodeColor colours the target red, main modules green, modules
+// sharing the first main module's domain blue and everything else orange.
+func classifyNodeColor(node, target string, mainModules []string) nodeColor {
+	if node == target {
 		return nodeColor{"#FFE0E0", "#D32F2F", "#B71C1C"}
 	}
-	if contains(result.MainModules, node) {
+	if contains(mainModules, node) {
 		return nodeColor{"#E8F5E9", "#388E3C", "#1B5E20"}
 	}
 	// Check if same org as first main module
-	if len(result.MainModules) > 0 {
-		main := result.MainModules[0]
+	if len(mainModules) > 0 {
+		main := mainModules[0]
 		if idx := strings.Index(main, "/"); idx > 0 {
 			prefix := main[:idx+1]
 			if strings.HasPrefix(node, prefix) {
@@ -310,28 +161,57 @@ func abbreviateModule(mod string, mainModules []string) string {
 }
 
 func svgBezierPath(from, to nodePos) string {
-	x1 := from.X + from.W/2
-	y1 := from.Y + from.H
-	x2 := to.X + to.W/2
-	y2 := to.Y
-	cx := (x1 + x2) / 2
-	cy := (y1 + y2) / 2
+	x1, y1, x2, y2 := svgEdgeEndpoints(from, to)
+	cx, cy := svgControlPoint(x1, y1, x2, y2)
 	return fmt.Sprintf("M%.1f %.1fQ%.1f %.1f %.1f %.1f", x1, y1, cx, cy, x2, y2)
 }
 
-func renderSVGLegend(b *strings.Builder, x, y float64) {
-	entries := []struct {
-		fill, stroke, label string
-	}{
-		{"#E8F5E9", "#388E3C", "Main module"},
-		{"#E3F2FD", "#1976D2", "Same org"},
-		{"#FFF3E0", "#F57C00", "External"},
-		{"#FFE0E0", "#D32F2F", "Target"},
+// svgEdgeEndpoints returns where an edge leaves from and enters to: the
+// bottom and top centers of the boxes.
+func svgEdgeEndpoints(from, to nodePos) (x1, y1, x2, y2 float64) {
+	x1, y1 = from.X+from.W/2, from.Y+from.H
+	x2, y2 = to.X+to.W/2, to.Y
+	if to.Y < from.Y {
+		// edge pointing upwards (e.g. closing a cycle): leave from the top
+		y1, y2 = from.Y, to.Y+to.H
+	}
+	return x1, y1, x2, y2
+}
+
+// svgControlPoint returns the control point of the curve from (x1, y1) to
+// (x2, y2). Upward edges bend to the right so that the two edges of a
+// two-node cycle do not overlap.
+func svgControlPoint(x1, y1, x2, y2 float64) (float64, float64) {
+	cx := (x1 + x2) / 2
+	cy := (y1 + y2) / 2
+	if y2 < y1 {
+		cx += svgUpwardEdgeBend
 	}
+	return cx, cy
+}
+
+// svgEdgeLabelPoint returns where the label of the edge between two boxes is
+// drawn: the middle of the curve drawn by svgBezierPath.
+func svgEdgeLabelPoint(from, to nodePos) (float64, float64) {
+	x1, y1, x2, y2 := svgEdgeEndpoints(from, to)
+	cx, cy := svgControlPoint(x1, y1, x2, y2)
+	// Point at t = 0.5 of the quadratic curve.
+	return (x1+2*cx+x2)/4 + 8, (y1 + 2*cy + y2) / 4
+}
+
+// svgDefaultLegend matches the colours of classifyNodeColor.
+var svgDefaultLegend = []svgLegendEntry{
+	{"#E8F5E9", "#388E3C", "Main module"},
+	{"#E3F2FD", "#1976D2", "Same org"},
+	{"#FFF3E0", "#F57C00", "External"},
+	{"#FFE0E0", "#D32F2F", "Target"},
+}
+
+func renderSVGLegend(b *strings.Builder, x, y float64, entries []svgLegendEntry) {
 	for i, e := range entries {
 		ex := x + float64(i)*110
-		fmt.Fprintf(b, `<rect x="%.0f" y="%.0f" width="12" height="12" rx="3" fill="%s" stroke="%s" stroke-width="1"/>`, ex, y, e.fill, e.stroke)
-		fmt.Fprintf(b, `<text x="%.0f" y="%.0f" font-size="11" dominant-baseline="central" fill="#555">%s</text>`, ex+16, y+6, e.label)
+		fmt.Fprintf(b, `<rect x="%.0f" y="%.0f" width="12" height="12" rx="3" fill="%s" stroke="%s" stroke-width="1"/>`, ex, y, e.Fill, e.Stroke)
+		fmt.Fprintf(b, `<text x="%.0f" y="%.0f" font-size="11" dominant-baseline="central" fill="#555">%s</text>`, ex+16, y+6, e.Label)
 	}
 	fmt.Fprintln(b)
 }
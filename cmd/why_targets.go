@@ -0,0 +1,178 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// WhyReport is the output of why when several dependencies or patterns are
+// given: one result per matched module, keyed by module path.
+type WhyReport struct {
+	MainModules []string             `json:"mainModules"`
+	Targets     map[string]WhyResult `json:"targets"`
+	// Unmatched lists the patterns that matched no module of the graph.
+	Unmatched []string `json:"unmatched,omitempty"`
+}
+
+// whyTargetList is the list of modules to explain, in command line order.
+type whyTargetList struct {
+	Targets   []string
+	Unmatched []string
+}
+
+// isModulePattern reports whether arg contains glob metacharacters.
+func isModulePattern(arg string) bool {
+	return strings.ContainsAny(arg, "*?[")
+}
+
+// expandWhyTargets expands the patterns in args against the modules of the
+// graph. Plain module paths are kept as they are, so that missing ones are
+// reported as not found; patterns matching nothing are listed as unmatched.
+func expandWhyTargets(args []string, allDeps []string) whyTargetList {
+	sortedDeps := append([]string{}, allDeps...)
+	sort.Strings(sortedDeps)
+
+	var list whyTargetList
+	seen := map[string]bool{}
+	add := func(target string) {
+		if !seen[target] {
+			seen[target] = true
+			list.Targets = append(list.Targets, target)
+		}
+	}
+	for _, arg := range args {
+		if !isModulePattern(arg) {
+			add(arg)
+			continue
+		}
+		matched := false
+		for _, dep := range sortedDeps {
+			if matchModulePattern(dep, arg) {
+				add(dep)
+				matched = true
+			}
+		}
+		if !matched {
+			list.Unmatched = append(list.Unmatched, arg)
+		}
+	}
+	return list
+}
+
+// runWhyTargets explains every target of list against the same graph.
+func runWhyTargets(depGraph *DependencyOverview, list whyTargetList) error {
+	allDeps := getAllDeps(depGraph.DirectDepList, depGraph.TransDepList)
+	results := make([]WhyResult, 0, len(list.Targets))
+	for _, target := range list.Targets {
+		results = append(results, analyzeWhy(depGraph, allDeps, target))
+	}
+
+	if whyPackages {
+		pkgGraph, err := loadPackageGraph(depGraph.MainModules)
+		if err != nil {
+			return err
+		}
+		for i := range results {
+			addPackageChains(&results[i], pkgGraph)
+		}
+	}
+
+	var found []WhyResult
+	for _, result := range results {
+		if result.Found {
+			found = append(found, result)
+		}
+	}
+	title := fmt.Sprintf("Why: %d modules", len(found))
+
+	if jsonOutput {
+		report := WhyReport{
+			MainModules: depGraph.MainModules,
+			Targets:     make(map[string]WhyResult, len(results)),
+			Unmatched:   list.Unmatched,
+		}
+		for _, result := range results {
+			report.Targets[result.Target] = result
+		}
+		out, err := json.MarshalIndent(report, "", "\t")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+		return nil
+	}
+	if whyMarkdown {
+		fmt.Print(renderWhyMarkdown(results, list.Unmatched, whyMarkdownBudget))
+		return nil
+	}
+	if dotOutput {
+		var annotations *dotAnnotations
+		if annotateOutput {
+			var err error
+			if annotations, err = newDOTAnnotations(depGraph, whyPathModules(found...), attributesPath); err != nil {
+				return err
+			}
+		}
+		return writeWhyDOT(title, found, annotations)
+	}
+	if svgOutput {
+		if len(whyPathModules(found...)) == 0 {
+			return outputWhySVG(WhyResult{Target: strings.Join(list.Targets, ", ")})
+		}
+		fmt.Print(renderWhySVG(title, whyReportSubtitle(found), found))
+		return nil
+	}
+	if mermaidOutput {
+		return writeWhyMermaid(title, found)
+	}
+	return outputWhyReportText(results, list.Unmatched)
+}
+
+// whyReportSubtitle summarises the targets of a combined SVG diagram.
+func whyReportSubtitle(found []WhyResult) string {
+	targets := make([]string, 0, len(found))
+	for _, result := range found {
+		targets = append(targets, fmt.Sprintf("%s (%d paths)", result.Target, len(result.Paths)))
+	}
+	return strings.Join(targets, ", ")
+}
+
+// outputWhyReportText prints a summary line followed by the usual report of
+// every target.
+func outputWhyReportText(results []WhyResult, unmatched []string) error {
+	found := 0
+	for _, result := range results {
+		if result.Found {
+			found++
+		}
+	}
+	fmt.Printf("Explaining %d modules (%d found in the dependency graph)\n", len(results), found)
+	if len(unmatched) > 0 {
+		fmt.Printf("No modules match: %s\n", strings.Join(unmatched, ", "))
+	}
+	for _, result := range results {
+		fmt.Println()
+		if err := outputWhyText(result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
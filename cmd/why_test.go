@@ -8,19 +8,62 @@ import (
 	"testing"
 )
 
-func TestFindAllPathsHonorsLimit(t *testing.T) {
+func TestKShortestPathsHonorsLimit(t *testing.T) {
 	graph := map[string][]string{
 		"A": {"B", "C"},
 		"B": {"D"},
 		"C": {"D"},
 	}
-	var out [][]string
-	findAllPaths("A", "D", graph, []string{}, map[string]bool{}, &out, 1)
+	out := kShortestPaths(graph, []string{"A"}, "D", 1)
 	if len(out) != 1 {
 		t.Fatalf("expected exactly 1 path due to limit, got %d (%v)", len(out), out)
 	}
 }
 
+func TestKShortestPathsReturnsShortestFirst(t *testing.T) {
+	// The long path is listed first, so a depth-first search finds it first.
+	graph := map[string][]string{
+		"M": {"A", "B", "X"},
+		"A": {"C"},
+		"C": {"E"},
+		"E": {"X"},
+		"B": {"X", "C"},
+		"N": {"B"},
+	}
+	got := kShortestPaths(graph, []string{"M", "N"}, "X", 3)
+	want := [][]string{{"M", "X"}, {"M", "B", "X"}, {"N", "B", "X"}}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if !isSliceSame(got[i], want[i]) {
+			t.Fatalf("expected %v, got %v", want, got)
+		}
+	}
+
+	all := kShortestPaths(graph, []string{"M", "N"}, "X", 0)
+	count, exact := countPaths(graph, []string{"M", "N"}, "X")
+	if !exact || count != len(all) || count != 6 {
+		t.Fatalf("expected an exact count of 6 matching %v, got %d (exact=%v)", all, count, exact)
+	}
+}
+
+func TestCountPathsIsLowerBoundWithCycles(t *testing.T) {
+	graph := map[string][]string{
+		"M": {"A"},
+		"A": {"B", "X"},
+		"B": {"A", "X"},
+	}
+	all := kShortestPaths(graph, []string{"M"}, "X", 0)
+	count, exact := countPaths(graph, []string{"M"}, "X")
+	if exact || count > len(all) || count < 1 {
+		t.Fatalf("expected a lower bound of the %d paths, got %d (exact=%v)", len(all), count, exact)
+	}
+	if len(all) != 2 {
+		t.Fatalf("expected 2 simple paths, got %v", all)
+	}
+}
+
 func TestOutputWhyDOTDeterministicOrder(t *testing.T) {
 	result := WhyResult{
 		Target:      "D",
@@ -55,6 +98,45 @@ func TestOutputWhyDOTDeterministicOrder(t *testing.T) {
 	}
 }
 
+func TestExpandWhyTargets(t *testing.T) {
+	deps := []string{"github.com/gogo/protobuf", "go.uber.org/zap", "github.com/gogo/googleapis", "github.com/golang/protobuf"}
+	list := expandWhyTargets([]string{"github.com/gogo/*", "go.uber.org/zap", "github.com/gogo/protobuf", "example.com/*", "example.com/missing"}, deps)
+
+	wantTargets := []string{"github.com/gogo/googleapis", "github.com/gogo/protobuf", "go.uber.org/zap", "example.com/missing"}
+	if !isSliceSame(list.Targets, wantTargets) {
+		t.Fatalf("targets = %v, want %v", list.Targets, wantTargets)
+	}
+	if !isSliceSame(list.Unmatched, []string{"example.com/*"}) {
+		t.Fatalf("unmatched = %v, want [example.com/*]", list.Unmatched)
+	}
+}
+
+func TestWriteWhyDOTHighlightsEveryTarget(t *testing.T) {
+	results := []WhyResult{
+		{Target: "C", Found: true, MainModules: []string{"A"}, Paths: []WhyPath{{Path: []string{"A", "B", "C"}}}},
+		{Target: "D", Found: true, MainModules: []string{"A"}, Paths: []WhyPath{{Path: []string{"A", "B", "D"}}}},
+	}
+
+	output := captureStdout(t, func() {
+		if err := writeWhyDOT("Why: 2 modules", results, nil); err != nil {
+			t.Fatalf("writeWhyDOT returned error: %v", err)
+		}
+	})
+
+	for _, want := range []string{
+		"\"C\" [fillcolor=\"#ffffcc\"];",
+		"\"D\" [fillcolor=\"#ffffcc\"];",
+		"\"B\" [fillcolor=\"white\"];",
+	} {
+		if !strings.Contains(output, want) {
+			t.Fatalf("expected %q in output:\n%s", want, output)
+		}
+	}
+	if strings.Count(output, "\"A\" -> \"B\";") != 1 {
+		t.Fatalf("expected the shared edge once, got output:\n%s", output)
+	}
+}
+
 func captureStdout(t *testing.T, fn func()) string {
 	t.Helper()
 	old := os.Stdout
@@ -2,7 +2,10 @@ module github.com/kubernetes-sigs/depstat
 
 go 1.22.0
 
-require github.com/spf13/cobra v1.8.1
+require (
+	github.com/santhosh-tekuri/jsonschema/v5 v5.3.1
+	github.com/spf13/cobra v1.8.1
+)
 
 require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
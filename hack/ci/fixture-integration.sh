@@ -171,10 +171,15 @@ grep -q 'edgetype="direct"' graph.dot \
 grep -q 'edgetype="transitive"' graph.dot \
   || { echo "FAIL: graph.dot missing transitive edges"; exit 1; }
 
+echo "==> Testing graph --svg..."
+"${DEPSTAT_BIN}" graph --svg > graph.svg
+grep -q '<svg' graph.svg \
+  || { echo "FAIL: graph --svg output missing '<svg' tag"; exit 1; }
+
 echo "==> Testing cycles --json..."
-"${DEPSTAT_BIN}" cycles --json > cycles.json
-jq -e '.cycles != null' cycles.json >/dev/null \
-  || { echo "FAIL: cycles JSON missing .cycles key"; exit 1; }
+"${DEPSTAT_BIN}" cycles --json > cycles.jsonl
+jq -s -e 'last | .totalCycles != null' cycles.jsonl >/dev/null \
+  || { echo "FAIL: cycles JSON Lines missing final totalCycles line"; exit 1; }
 
 echo "==> Testing cycles --summary..."
 "${DEPSTAT_BIN}" cycles --summary > cycles-summary.txt
@@ -187,9 +192,14 @@ jq -e '.summary.totalCycles >= 0' cycles-summary.json >/dev/null \
   || { echo "FAIL: cycles --summary --json missing summary.totalCycles"; exit 1; }
 
 echo "==> Testing cycles --max-length 2 --json..."
-"${DEPSTAT_BIN}" cycles --max-length 2 --json > cycles-max2.json
-jq -e '.cycles != null' cycles-max2.json >/dev/null \
-  || { echo "FAIL: cycles --max-length 2 --json missing .cycles key"; exit 1; }
+"${DEPSTAT_BIN}" cycles --max-length 2 --json > cycles-max2.jsonl
+jq -s -e 'last | .totalCycles != null' cycles-max2.jsonl >/dev/null \
+  || { echo "FAIL: cycles --max-length 2 --json missing final totalCycles line"; exit 1; }
+
+echo "==> Testing cycles --max-cycles 1 --json..."
+"${DEPSTAT_BIN}" cycles --max-cycles 1 --json > cycles-max-cycles.jsonl
+jq -s -e 'last | .totalCycles <= 1' cycles-max-cycles.jsonl >/dev/null \
+  || { echo "FAIL: cycles --max-cycles 1 --json reported more than one cycle"; exit 1; }
 
 echo "==> Testing why --json..."
 "${DEPSTAT_BIN}" why example.com/c --json > why.json
@@ -232,6 +242,11 @@ echo "==> Testing diff --dot..."
 grep -q 'strict digraph' diff.dot \
   || { echo "FAIL: diff --dot output missing 'strict digraph'"; exit 1; }
 
+echo "==> Testing diff --svg (native, no graphviz)..."
+"${DEPSTAT_BIN}" diff HEAD~1 HEAD --svg > diff.svg
+grep -q '<svg' diff.svg \
+  || { echo "FAIL: diff --svg output missing '<svg' tag"; exit 1; }
+
 echo "==> Testing diff-then-why loop (Prow presubmit pattern)..."
 for dep in $(jq -r '.added[]?' diff.json); do
   "${DEPSTAT_BIN}" why "${dep}" --json > /dev/null || true